@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/yourusername/phpvm/data"
+)
+
+var aliasDeleteFlag bool
+
+var aliasCmd = &cobra.Command{
+	Use:   "alias [name] [version]",
+	Short: "Show or set version aliases",
+	Long: `Give an installed PHP version a name, such as "ci" or "legacy", that can be
+used anywhere a version is accepted: switch, --version flags and .php-version
+files. Without arguments all aliases are listed; with a name only, its
+target is shown. 'phpvm upgrade' moves aliases to the new patch release.`,
+	Args: cobra.MaximumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		switch {
+		case aliasDeleteFlag:
+			if len(args) != 1 {
+				return fmt.Errorf("--delete takes exactly one alias name")
+			}
+			return deleteAlias(args[0])
+		case len(args) == 0:
+			return listAliases()
+		case len(args) == 1:
+			return showAlias(args[0])
+		}
+		return setAlias(args[0], args[1])
+	},
+}
+
+func init() {
+	aliasCmd.Flags().BoolVar(&aliasDeleteFlag, "delete", false, "remove the alias")
+	RootCmd.AddCommand(aliasCmd)
+}
+
+// aliasesPath returns the file holding version aliases
+func aliasesPath() (string, error) {
+	root, err := phpvmHome()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(root, "aliases.json"), nil
+}
+
+// loadAliases reads the alias name to version map
+func loadAliases() (map[string]string, error) {
+	path, err := aliasesPath()
+	if err != nil {
+		return nil, err
+	}
+
+	aliases := map[string]string{}
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return aliases, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", path, err)
+	}
+	if err := json.Unmarshal(content, &aliases); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", path, err)
+	}
+	return aliases, nil
+}
+
+// saveAliases writes the alias map
+func saveAliases(aliases map[string]string) error {
+	path, err := aliasesPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %v", filepath.Dir(path), err)
+	}
+
+	content, err := json.MarshalIndent(aliases, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode aliases: %v", err)
+	}
+	if err := os.WriteFile(path, append(content, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %v", path, err)
+	}
+	return nil
+}
+
+// resolveAlias returns the version an alias points at, or an empty string
+// when name isn't an alias
+func resolveAlias(name string) (string, error) {
+	aliases, err := loadAliases()
+	if err != nil {
+		return "", err
+	}
+	return aliases[name], nil
+}
+
+// retargetAliases points every alias targeting from at to instead, returning
+// the names that were changed
+func retargetAliases(from, to string) ([]string, error) {
+	aliases, err := loadAliases()
+	if err != nil {
+		return nil, err
+	}
+
+	var changed []string
+	for name, target := range aliases {
+		if target == from {
+			aliases[name] = to
+			changed = append(changed, name)
+		}
+	}
+	if len(changed) == 0 {
+		return nil, nil
+	}
+	sort.Strings(changed)
+	return changed, saveAliases(aliases)
+}
+
+func listAliases() error {
+	aliases, err := loadAliases()
+	if err != nil {
+		return err
+	}
+	if len(aliases) == 0 {
+		fmt.Println("No aliases defined. Use 'phpvm alias <name> <version>' to add one")
+		return nil
+	}
+
+	names := make([]string, 0, len(aliases))
+	for name := range aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		note := ""
+		if aliases[name] != systemVersion && !isVersionInstalled(aliases[name]) {
+			note = " (not installed)"
+		}
+		fmt.Printf("%-16s -> %s%s\n", name, aliases[name], note)
+	}
+	return nil
+}
+
+func showAlias(name string) error {
+	target, err := resolveAlias(name)
+	if err != nil {
+		return err
+	}
+	if target == "" {
+		return fmt.Errorf("no alias named %q", name)
+	}
+	fmt.Println(target)
+	return nil
+}
+
+func setAlias(name, version string) error {
+	if _, err := data.ParseVersion(name); err == nil || name == data.NightlyVersion || name == systemVersion {
+		return fmt.Errorf("alias %q would shadow a PHP version", name)
+	}
+
+	resolution, err := matchInstalled(version, "the command line")
+	if err != nil {
+		return err
+	}
+	if resolution.Version == "" {
+		return fmt.Errorf("PHP version %s is not installed. Use 'phpvm install %s' first", version, version)
+	}
+
+	aliases, err := loadAliases()
+	if err != nil {
+		return err
+	}
+	aliases[name] = resolution.Version
+	if err := saveAliases(aliases); err != nil {
+		return err
+	}
+	fmt.Printf("✅ %s -> %s\n", name, resolution.Version)
+	return nil
+}
+
+func deleteAlias(name string) error {
+	aliases, err := loadAliases()
+	if err != nil {
+		return err
+	}
+	if _, ok := aliases[name]; !ok {
+		return fmt.Errorf("no alias named %q", name)
+	}
+	delete(aliases, name)
+	if err := saveAliases(aliases); err != nil {
+		return err
+	}
+	fmt.Printf("✅ Removed alias %s\n", name)
+	return nil
+}
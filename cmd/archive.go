@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+// extractTarGz unpacks a .tar.gz/.tgz archive into destDir
+func extractTarGz(archivePath, destDir string) error {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return fmt.Errorf("failed to read gzip stream: %v", err)
+	}
+	defer gz.Close()
+
+	return extractTar(gz, destDir)
+}
+
+// extractTar unpacks a tar stream into destDir, refusing entries that would
+// escape it, either by name or through a symlink. Archives come from
+// downloads, --from and bundles, so none of them is trusted.
+func extractTar(r io.Reader, destDir string) error {
+	destDir, err := filepath.Abs(destDir)
+	if err != nil {
+		return err
+	}
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read archive: %v", err)
+		}
+
+		target := filepath.Join(destDir, header.Name)
+		if !withinDir(destDir, target) {
+			return fmt.Errorf("archive entry %s escapes the destination directory", header.Name)
+		}
+		if err := checkNoSymlinkParents(destDir, target); err != nil {
+			return fmt.Errorf("archive entry %s: %v", header.Name, err)
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if info, err := os.Lstat(target); err == nil && info.Mode()&os.ModeSymlink != 0 {
+				return fmt.Errorf("archive entry %s replaces a symlink with a directory", header.Name)
+			}
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			if err := removeExisting(target); err != nil {
+				return err
+			}
+			out, err := os.OpenFile(target, os.O_CREATE|os.O_EXCL|os.O_WRONLY|syscall.O_NOFOLLOW, os.FileMode(header.Mode)&0777)
+			if err != nil {
+				return err
+			}
+			if _, err := io.Copy(out, tr); err != nil {
+				out.Close()
+				return err
+			}
+			if err := out.Close(); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			if filepath.IsAbs(header.Linkname) || !withinDir(destDir, filepath.Join(filepath.Dir(target), header.Linkname)) {
+				return fmt.Errorf("archive entry %s links outside the destination directory (%s)", header.Name, header.Linkname)
+			}
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			if err := removeExisting(target); err != nil {
+				return err
+			}
+			if err := os.Symlink(header.Linkname, target); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// withinDir reports whether path is dir or lies inside it, lexically
+func withinDir(dir, path string) bool {
+	path = filepath.Clean(path)
+	return path == dir || strings.HasPrefix(path, dir+string(os.PathSeparator))
+}
+
+// checkNoSymlinkParents fails when any existing directory between dir and
+// path is a symlink, which an earlier archive entry could have pointed
+// anywhere
+func checkNoSymlinkParents(dir, path string) error {
+	rel, err := filepath.Rel(dir, filepath.Dir(path))
+	if err != nil || rel == "." {
+		return err
+	}
+	current := dir
+	for _, part := range strings.Split(rel, string(os.PathSeparator)) {
+		current = filepath.Join(current, part)
+		info, err := os.Lstat(current)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			return fmt.Errorf("%s is a symlink", current)
+		}
+	}
+	return nil
+}
+
+// removeExisting removes a file or symlink at path so a new entry never
+// writes through it
+func removeExisting(path string) error {
+	info, err := os.Lstat(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		return fmt.Errorf("%s is a directory", path)
+	}
+	return os.Remove(path)
+}
+
+// copyFile copies src to dst with the given permissions
+func copyFile(src, dst string, perm os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"archive/tar"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// tarEntry is one entry of a test archive
+type tarEntry struct {
+	Name     string
+	Body     string
+	Linkname string
+	Type     byte
+}
+
+func buildTar(t *testing.T, entries []tarEntry) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, entry := range entries {
+		header := &tar.Header{Name: entry.Name, Mode: 0644, Typeflag: entry.Type, Linkname: entry.Linkname}
+		switch entry.Type {
+		case tar.TypeReg:
+			header.Size = int64(len(entry.Body))
+		case tar.TypeDir:
+			header.Mode = 0755
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		if entry.Type == tar.TypeReg {
+			if _, err := tw.Write([]byte(entry.Body)); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return &buf
+}
+
+func TestExtractTar(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []tarEntry
+		wantErr bool
+	}{
+		{
+			name: "prefix with relative link",
+			entries: []tarEntry{
+				{Name: "php-8.3/", Type: tar.TypeDir},
+				{Name: "php-8.3/bin/php", Body: "php", Type: tar.TypeReg},
+				{Name: "php-8.3/php", Linkname: "bin/php", Type: tar.TypeSymlink},
+			},
+		},
+		{
+			name:    "dot-dot entry",
+			entries: []tarEntry{{Name: "../escaped", Body: "x", Type: tar.TypeReg}},
+			wantErr: true,
+		},
+		{
+			name:    "nested dot-dot entry",
+			entries: []tarEntry{{Name: "php/../../escaped", Body: "x", Type: tar.TypeReg}},
+			wantErr: true,
+		},
+		{
+			name:    "absolute symlink",
+			entries: []tarEntry{{Name: "etc", Linkname: "/etc", Type: tar.TypeSymlink}},
+			wantErr: true,
+		},
+		{
+			name:    "relative symlink out of the destination",
+			entries: []tarEntry{{Name: "php/up", Linkname: "../../..", Type: tar.TypeSymlink}},
+			wantErr: true,
+		},
+		{
+			name: "write through a symlinked directory",
+			entries: []tarEntry{
+				{Name: "lib", Linkname: ".", Type: tar.TypeSymlink},
+				{Name: "lib/php.so", Body: "x", Type: tar.TypeReg},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parent := t.TempDir()
+			dest := filepath.Join(parent, "dest")
+			if err := os.Mkdir(dest, 0755); err != nil {
+				t.Fatal(err)
+			}
+
+			err := extractTar(buildTar(t, tt.entries), dest)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("extractTar() succeeded, want an error")
+				}
+				if _, err := os.Lstat(filepath.Join(parent, "escaped")); err == nil {
+					t.Error("extractTar() wrote outside the destination")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("extractTar() failed: %v", err)
+			}
+		})
+	}
+}
+
+func TestExtractTarDoesNotWriteThroughLinks(t *testing.T) {
+	dest := t.TempDir()
+	entries := []tarEntry{
+		{Name: "target", Body: "keep", Type: tar.TypeReg},
+		{Name: "link", Linkname: "target", Type: tar.TypeSymlink},
+		{Name: "link", Body: "overwritten", Type: tar.TypeReg},
+	}
+	if err := extractTar(buildTar(t, entries), dest); err != nil {
+		t.Fatalf("extractTar() failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dest, "target"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "keep" {
+		t.Errorf("target = %q, want it untouched", content)
+	}
+	if info, err := os.Lstat(filepath.Join(dest, "link")); err != nil || !info.Mode().IsRegular() {
+		t.Errorf("link should have been replaced by a regular file")
+	}
+}
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/yourusername/phpvm/data"
+)
+
+// buildPHP downloads a php-src tarball and compiles it with installDir as
+// its prefix, so php-config, phpize and extension builds work as usual.
+// installDir/php is linked to the built binary. Extra configure options can
+// be passed in PHPVM_CONFIGURE_OPTIONS.
+func buildPHP(version, sourceURL, installDir string) error {
+	workDir, err := os.MkdirTemp("", "phpvm-build-")
+	if err != nil {
+		return fmt.Errorf("failed to create build directory: %v", err)
+	}
+	defer os.RemoveAll(workDir)
+
+	tarball := filepath.Join(workDir, "php-src.tar.gz")
+	fmt.Printf("Downloading PHP %s source from %s...\n", version, sourceURL)
+	// Nightly tarballs change with every php-src commit, so they aren't cached
+	download := cachedDownload
+	if version == data.NightlyVersion {
+		download = downloadFile
+	}
+	if err := download(sourceURL, tarball); err != nil {
+		return fmt.Errorf("failed to download PHP source: %v", err)
+	}
+	if err := extractTarGz(tarball, workDir); err != nil {
+		return fmt.Errorf("failed to extract PHP source: %v", err)
+	}
+
+	srcDir, err := findPHPSource(workDir)
+	if err != nil {
+		return err
+	}
+
+	configure := []string{
+		"./configure",
+		"--prefix=" + installDir,
+		"--with-config-file-path=" + installDir,
+		"--with-config-file-scan-dir=" + filepath.Join(installDir, "conf.d"),
+		"--disable-cgi",
+	}
+	configure = append(configure, strings.Fields(os.Getenv("PHPVM_CONFIGURE_OPTIONS"))...)
+
+	var steps [][]string
+	// Git snapshots ship without a generated configure script
+	if _, err := os.Stat(filepath.Join(srcDir, "configure")); err != nil {
+		steps = append(steps, []string{"./buildconf", "--force"})
+	}
+	steps = append(steps,
+		configure,
+		[]string{"make", fmt.Sprintf("-j%d", runtime.NumCPU())},
+		[]string{"make", "install"},
+	)
+
+	fmt.Printf("Building PHP %s (this can take a while)...\n", version)
+	for _, step := range steps {
+		fmt.Printf("  %s\n", strings.Join(step, " "))
+		if err := runBuildStep(srcDir, step[0], step[1:]...); err != nil {
+			return err
+		}
+	}
+
+	phpBinary := filepath.Join(installDir, "php")
+	if _, err := os.Lstat(phpBinary); err == nil {
+		if err := os.Remove(phpBinary); err != nil {
+			return fmt.Errorf("failed to replace %s: %v", phpBinary, err)
+		}
+	}
+	if err := os.Symlink(filepath.Join("bin", "php"), phpBinary); err != nil {
+		return fmt.Errorf("failed to link PHP binary: %v", err)
+	}
+
+	meta, err := loadMetadata(version)
+	if err != nil {
+		return err
+	}
+	meta.Build = &SourceBuild{URL: sourceURL, BuiltAt: time.Now().UTC()}
+	meta.Modules = nil
+	return saveMetadata(meta)
+}
+
+// findPHPSource returns the unpacked php-src directory of a tarball
+func findPHPSource(workDir string) (string, error) {
+	matches, _ := filepath.Glob(filepath.Join(workDir, "*", "main", "php_version.h"))
+	if len(matches) == 0 {
+		return "", fmt.Errorf("no PHP source tree found in the downloaded tarball")
+	}
+	return filepath.Dir(filepath.Dir(matches[0])), nil
+}
@@ -0,0 +1,711 @@
+package cmd
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yourusername/phpvm/data"
+)
+
+var (
+	bundleOutputFlag       string
+	bundleWithComposerFlag bool
+)
+
+var bundleCmd = &cobra.Command{
+	Use:   "bundle",
+	Short: "Move PHP versions to machines without network access",
+	Long: `Package installed PHP versions into a single archive and install them from
+it on another machine, with no network access needed there. Archives are
+.tar.gz, or .tar.zst when the zstd command is available.`,
+}
+
+var bundleCreateCmd = &cobra.Command{
+	Use:   "create <version...>",
+	Short: "Package installed PHP versions into a bundle",
+	Long: `Package installed PHP versions (with their php.ini, conf.d and built
+extensions) into a bundle. With --with-composer the Composer phars they use
+and the Composer versions manifest are included too. Every file is listed
+with its SHA-256 in the bundle, and checked when it is installed.`,
+	Args:         cobra.MinimumNArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if bundleOutputFlag == "" {
+			return fmt.Errorf("an output file is required (-o phpvm-bundle.tar.gz)")
+		}
+		return createBundle(args, bundleWithComposerFlag, bundleOutputFlag)
+	},
+}
+
+var bundleInstallCmd = &cobra.Command{
+	Use:   "install <bundle>",
+	Short: "Install the PHP versions in a bundle",
+	Long: `Install the PHP versions and Composer releases in a bundle made with
+'phpvm bundle create'. Versions that are already installed are skipped.`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return installBundle(args[0])
+	},
+}
+
+func init() {
+	bundleCreateCmd.Flags().StringVarP(&bundleOutputFlag, "output", "o", "", "bundle file to write (.tar.gz or .tar.zst)")
+	bundleCreateCmd.Flags().BoolVar(&bundleWithComposerFlag, "with-composer", false, "include the Composer phars the versions use")
+	bundleCmd.AddCommand(bundleCreateCmd, bundleInstallCmd)
+	RootCmd.AddCommand(bundleCmd)
+}
+
+// bundleManifestFile and bundleChecksumsFile are the bookkeeping files at
+// the top of a bundle
+const (
+	bundleManifestFile  = "bundle.json"
+	bundleChecksumsFile = "SHA256SUMS"
+)
+
+// BundleManifest describes the contents of a bundle
+type BundleManifest struct {
+	Format    int             `json:"format"`
+	CreatedAt time.Time       `json:"created_at"`
+	Root      string          `json:"root"` // ~/.phpvm of the machine the bundle was made on
+	Versions  []BundleVersion `json:"versions"`
+	Composer  []string        `json:"composer,omitempty"`
+	// Symlinks in the bundle and their targets, which SHA256SUMS can't cover
+	Links map[string]string `json:"links,omitempty"`
+}
+
+// BundleVersion is a PHP version in a bundle and the Composer release it
+// was linked to
+type BundleVersion struct {
+	Version  string `json:"version"`
+	Composer string `json:"composer,omitempty"`
+}
+
+// bundleSkippedEntries are version directory entries that are generated
+// per machine and recreated on install
+var bundleSkippedEntries = map[string]bool{
+	"composer":      true,
+	"composer-home": true,
+	"shims":         true,
+}
+
+// bundleCompression picks the compression for a bundle file from its name
+func bundleCompression(path string) (string, error) {
+	switch {
+	case strings.HasSuffix(path, ".tar.gz"), strings.HasSuffix(path, ".tgz"):
+		return "gzip", nil
+	case strings.HasSuffix(path, ".tar.zst"), strings.HasSuffix(path, ".tzst"):
+		if _, err := exec.LookPath("zstd"); err != nil {
+			return "", fmt.Errorf("zstd is needed for .tar.zst bundles but wasn't found on PATH. Use .tar.gz instead")
+		}
+		return "zstd", nil
+	}
+	return "", fmt.Errorf("unsupported bundle name %s. Use .tar.gz or .tar.zst", filepath.Base(path))
+}
+
+func createBundle(requests []string, withComposer bool, output string) error {
+	compression, err := bundleCompression(output)
+	if err != nil {
+		return err
+	}
+	root, err := phpvmHome()
+	if err != nil {
+		return err
+	}
+
+	manifest := BundleManifest{Format: 1, CreatedAt: time.Now().UTC(), Root: root}
+	composerSeen := map[string]bool{}
+	for _, request := range requests {
+		resolution, err := matchInstalled(request, "the command line")
+		if err != nil {
+			return err
+		}
+		if resolution.Version == "" {
+			return fmt.Errorf("PHP version %s is not installed", request)
+		}
+		if resolution.Version == systemVersion {
+			return fmt.Errorf("the system PHP isn't managed by phpvm and can't be bundled")
+		}
+		meta, err := loadMetadata(resolution.Version)
+		if err != nil {
+			return err
+		}
+		if meta.Imported != nil {
+			return fmt.Errorf("PHP %s was imported from %s and can't be bundled", resolution.Version, meta.Imported.Binary)
+		}
+		if err := checkBundledName(resolution.Version); err != nil {
+			return fmt.Errorf("PHP %s can't be bundled: bundles only hold versions named by their PHP version", resolution.Version)
+		}
+
+		entry := BundleVersion{Version: resolution.Version}
+		if withComposer {
+			composerVersion, _, err := versionComposerVersion(resolution.Version)
+			if err != nil {
+				return err
+			}
+			pharPath, err := composerPharPath(composerVersion.Version)
+			if err != nil {
+				return err
+			}
+			if _, err := os.Stat(pharPath); err != nil {
+				return fmt.Errorf("Composer %s is not installed. Use 'phpvm composer install %s' first", composerVersion.Version, composerVersion.Version)
+			}
+			entry.Composer = composerVersion.Version
+			if !composerSeen[entry.Composer] {
+				composerSeen[entry.Composer] = true
+				manifest.Composer = append(manifest.Composer, entry.Composer)
+			}
+		}
+		manifest.Versions = append(manifest.Versions, entry)
+	}
+
+	// Collect the files, relative to ~/.phpvm
+	var files []string
+	for _, entry := range manifest.Versions {
+		dir := filepath.Join(root, "versions", entry.Version)
+		err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			rel, _ := filepath.Rel(root, path)
+			if filepath.Dir(path) == dir && bundleSkippedEntries[d.Name()] {
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !d.IsDir() {
+				files = append(files, rel)
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to read PHP %s: %v", entry.Version, err)
+		}
+	}
+	for _, version := range manifest.Composer {
+		phar := filepath.Join("composer", version, "composer.phar")
+		files = append(files, phar)
+		if _, err := os.Stat(filepath.Join(root, phar+".sha256")); err == nil {
+			files = append(files, phar+".sha256")
+		}
+	}
+	if withComposer {
+		if manifestPath, err := composerManifestPath(); err == nil {
+			if _, err := os.Stat(manifestPath); err == nil {
+				files = append(files, filepath.Base(manifestPath))
+			}
+		}
+	}
+
+	for _, file := range files {
+		info, err := os.Lstat(filepath.Join(root, file))
+		if err != nil {
+			return err
+		}
+		if info.Mode()&os.ModeSymlink == 0 {
+			continue
+		}
+		target, err := os.Readlink(filepath.Join(root, file))
+		if err != nil {
+			return err
+		}
+		if manifest.Links == nil {
+			manifest.Links = map[string]string{}
+		}
+		manifest.Links[filepath.ToSlash(file)] = target
+	}
+
+	content, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode bundle manifest: %v", err)
+	}
+	content = append(content, '\n')
+
+	var sums strings.Builder
+	fmt.Fprintf(&sums, "%s  %s\n", sha256Bytes(content), bundleManifestFile)
+	for _, file := range files {
+		info, err := os.Lstat(filepath.Join(root, file))
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			continue
+		}
+		sum, err := sha256File(filepath.Join(root, file))
+		if err != nil {
+			return fmt.Errorf("failed to hash %s: %v", file, err)
+		}
+		fmt.Fprintf(&sums, "%s  %s\n", sum, filepath.ToSlash(file))
+	}
+
+	fmt.Printf("Creating %s...\n", output)
+	err = writeBundle(output, compression, func(tw *tar.Writer) error {
+		if err := addTarBytes(tw, bundleManifestFile, content); err != nil {
+			return err
+		}
+		if err := addTarBytes(tw, bundleChecksumsFile, []byte(sums.String())); err != nil {
+			return err
+		}
+		for _, file := range files {
+			if err := addTarFile(tw, filepath.Join(root, file), filepath.ToSlash(file)); err != nil {
+				return fmt.Errorf("failed to add %s: %v", file, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		os.Remove(output)
+		return err
+	}
+
+	for _, entry := range manifest.Versions {
+		if entry.Composer != "" {
+			fmt.Printf("  PHP %s (Composer %s)\n", entry.Version, entry.Composer)
+		} else {
+			fmt.Printf("  PHP %s\n", entry.Version)
+		}
+	}
+	fmt.Printf("✅ Bundled %d version(s) into %s\n", len(manifest.Versions), output)
+	fmt.Printf("Use 'phpvm bundle install %s' on the target machine\n", filepath.Base(output))
+	return nil
+}
+
+// writeBundle creates a compressed tar archive at path, letting fill write
+// its entries
+func writeBundle(path, compression string, fill func(tw *tar.Writer) error) error {
+	out, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %v", path, err)
+	}
+	defer out.Close()
+
+	if compression == "zstd" {
+		zstd := exec.Command("zstd", "-q", "-c")
+		zstd.Stdout = out
+		zstd.Stderr = os.Stderr
+		stdin, err := zstd.StdinPipe()
+		if err != nil {
+			return err
+		}
+		if err := zstd.Start(); err != nil {
+			return fmt.Errorf("failed to run zstd: %v", err)
+		}
+		tw := tar.NewWriter(stdin)
+		err = fill(tw)
+		if err == nil {
+			err = tw.Close()
+		}
+		stdin.Close()
+		if waitErr := zstd.Wait(); err == nil && waitErr != nil {
+			err = fmt.Errorf("zstd failed: %v", waitErr)
+		}
+		if err != nil {
+			return err
+		}
+		return out.Close()
+	}
+
+	gz := gzip.NewWriter(out)
+	tw := tar.NewWriter(gz)
+	if err := fill(tw); err != nil {
+		return err
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	return out.Close()
+}
+
+// addTarBytes adds an in-memory file to a tar archive
+func addTarBytes(tw *tar.Writer, name string, content []byte) error {
+	header := &tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), ModTime: time.Now(), Typeflag: tar.TypeReg}
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err := tw.Write(content)
+	return err
+}
+
+// addTarFile adds a regular file or symlink to a tar archive under name
+func addTarFile(tw *tar.Writer, path, name string) error {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return err
+	}
+	link := ""
+	if info.Mode()&os.ModeSymlink != 0 {
+		if link, err = os.Readlink(path); err != nil {
+			return err
+		}
+	}
+	header, err := tar.FileInfoHeader(info, link)
+	if err != nil {
+		return err
+	}
+	header.Name = name
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+	if !info.Mode().IsRegular() {
+		return nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = io.Copy(tw, file)
+	return err
+}
+
+// extractBundle unpacks a bundle into destDir
+func extractBundle(path, destDir string) error {
+	kind, err := detectArchive(path)
+	if err != nil {
+		return err
+	}
+	if kind == "gzip" {
+		return extractTarGz(path, destDir)
+	}
+	if !strings.HasSuffix(path, ".zst") && !strings.HasSuffix(path, ".tzst") {
+		return fmt.Errorf("%s is not a phpvm bundle", path)
+	}
+	if _, err := exec.LookPath("zstd"); err != nil {
+		return fmt.Errorf("zstd is needed to read %s but wasn't found on PATH", filepath.Base(path))
+	}
+
+	zstd := exec.Command("zstd", "-q", "-d", "-c", path)
+	zstd.Stderr = os.Stderr
+	stdout, err := zstd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := zstd.Start(); err != nil {
+		return fmt.Errorf("failed to run zstd: %v", err)
+	}
+	err = extractTar(stdout, destDir)
+	// Drain the pipe so zstd can exit if extraction stopped early
+	io.Copy(io.Discard, stdout)
+	if waitErr := zstd.Wait(); err == nil && waitErr != nil {
+		err = fmt.Errorf("zstd failed: %v", waitErr)
+	}
+	return err
+}
+
+// verifyBundle checks every file listed in an unpacked bundle's SHA256SUMS
+// and every symlink listed in its manifest, fails if anything else was
+// unpacked, and returns the manifest
+func verifyBundle(dir string) (*BundleManifest, error) {
+	file, err := os.Open(filepath.Join(dir, bundleChecksumsFile))
+	if err != nil {
+		return nil, fmt.Errorf("the bundle has no %s: %v", bundleChecksumsFile, err)
+	}
+	defer file.Close()
+
+	listed := map[string]bool{bundleChecksumsFile: true}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		sum, name, ok := strings.Cut(scanner.Text(), "  ")
+		if !ok {
+			return nil, fmt.Errorf("malformed %s line: %q", bundleChecksumsFile, scanner.Text())
+		}
+		if err := verifySHA256(filepath.Join(dir, filepath.FromSlash(name)), sum); err != nil {
+			return nil, err
+		}
+		listed[name] = true
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", bundleChecksumsFile, err)
+	}
+	if !listed[bundleManifestFile] {
+		return nil, fmt.Errorf("%s doesn't list %s", bundleChecksumsFile, bundleManifestFile)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, bundleManifestFile))
+	if err != nil {
+		return nil, fmt.Errorf("the bundle has no %s: %v", bundleManifestFile, err)
+	}
+	var manifest BundleManifest
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", bundleManifestFile, err)
+	}
+
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(dir, path)
+		name := filepath.ToSlash(rel)
+		switch {
+		case d.IsDir():
+			return nil
+		case d.Type().IsRegular():
+			if !listed[name] {
+				return fmt.Errorf("%s isn't listed in %s", name, bundleChecksumsFile)
+			}
+		case d.Type()&fs.ModeSymlink != 0:
+			want, ok := manifest.Links[name]
+			if !ok {
+				return fmt.Errorf("symlink %s isn't listed in %s", name, bundleManifestFile)
+			}
+			if target, err := os.Readlink(path); err != nil || target != want {
+				return fmt.Errorf("symlink %s doesn't point at %s", name, want)
+			}
+			// Links are installed with their version, so they must stay
+			// inside it rather than reach other phpvm state
+			parts := strings.SplitN(name, "/", 3)
+			if len(parts) < 3 || parts[0] != "versions" || filepath.IsAbs(want) {
+				return fmt.Errorf("symlink %s points outside its PHP version", name)
+			}
+			resolved := filepath.Join(filepath.Dir(rel), want)
+			if !withinDir(filepath.Join(parts[0], parts[1]), resolved) {
+				return fmt.Errorf("symlink %s points outside its PHP version", name)
+			}
+		default:
+			return fmt.Errorf("%s isn't a regular file or symlink", name)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for name := range manifest.Links {
+		if _, err := os.Lstat(filepath.Join(dir, filepath.FromSlash(name))); err != nil {
+			return nil, fmt.Errorf("symlink %s listed in %s is missing", name, bundleManifestFile)
+		}
+	}
+	return &manifest, nil
+}
+
+func installBundle(path string) error {
+	root, err := phpvmHome()
+	if err != nil {
+		return err
+	}
+	stagingRoot := filepath.Join(root, "staging")
+	if err := os.MkdirAll(stagingRoot, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %v", stagingRoot, err)
+	}
+	staging, err := os.MkdirTemp(stagingRoot, "bundle-")
+	if err != nil {
+		return fmt.Errorf("failed to create staging directory: %v", err)
+	}
+	defer os.RemoveAll(staging)
+
+	fmt.Printf("Unpacking %s...\n", path)
+	if err := extractBundle(path, staging); err != nil {
+		return fmt.Errorf("failed to unpack %s: %v", path, err)
+	}
+	manifest, err := verifyBundle(staging)
+	if err != nil {
+		return fmt.Errorf("refusing to install %s: %v", path, err)
+	}
+	if manifest.Format != 1 {
+		return fmt.Errorf("unsupported bundle format %d. Upgrade phpvm", manifest.Format)
+	}
+	// Names become paths under ~/.phpvm, so check them all before anything
+	// is installed
+	for _, version := range manifest.Composer {
+		if err := checkComposerVersionName(version); err != nil {
+			return fmt.Errorf("refusing to install %s: %v", path, err)
+		}
+	}
+	for _, entry := range manifest.Versions {
+		if err := checkBundledName(entry.Version); err != nil {
+			return fmt.Errorf("refusing to install %s: %v", path, err)
+		}
+		if entry.Composer != "" && !slices.Contains(manifest.Composer, entry.Composer) {
+			return fmt.Errorf("refusing to install %s: PHP %s uses Composer %s, which the bundle doesn't hold", path, entry.Version, entry.Composer)
+		}
+	}
+	fmt.Printf("✅ Verified bundle created %s\n", manifest.CreatedAt.Format("2006-01-02 15:04"))
+
+	for _, version := range manifest.Composer {
+		if err := installBundledComposer(staging, version); err != nil {
+			return err
+		}
+	}
+	installBundledCatalog(staging, manifest.Composer)
+
+	installed := 0
+	for _, entry := range manifest.Versions {
+		if isVersionInstalled(entry.Version) {
+			fmt.Printf("ℹ️  PHP %s is already installed, skipping\n", entry.Version)
+			continue
+		}
+		if err := installBundledVersion(staging, manifest.Root, entry); err != nil {
+			return err
+		}
+		installed++
+	}
+
+	fmt.Printf("✅ Installed %d of %d version(s) from %s\n", installed, len(manifest.Versions), filepath.Base(path))
+	return nil
+}
+
+// installBundledComposer moves a Composer phar and its checksum from an
+// unpacked bundle into ~/.phpvm/composer unless it is already there
+func installBundledComposer(staging, version string) error {
+	pharPath, err := composerPharPath(version)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(pharPath); err == nil {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(pharPath), 0755); err != nil {
+		return fmt.Errorf("failed to create Composer directory: %v", err)
+	}
+
+	src := filepath.Join(staging, "composer", version, "composer.phar")
+	if err := verifyPharSignature(src); err != nil {
+		return fmt.Errorf("refusing to install Composer %s: %v", version, err)
+	}
+	if err := os.Rename(src, pharPath); err != nil {
+		return fmt.Errorf("failed to install Composer %s: %v", version, err)
+	}
+
+	// Keep the checksum so the phar can be re-verified offline
+	sum, err := sha256File(pharPath)
+	if err != nil {
+		return fmt.Errorf("failed to hash Composer %s: %v", version, err)
+	}
+	if err := os.WriteFile(pharPath+".sha256", []byte(sum+"\n"), 0644); err != nil {
+		fmt.Printf("⚠️  Warning: Failed to store Composer checksum: %v\n", err)
+	}
+	fmt.Printf("✅ Installed Composer %s\n", version)
+	return nil
+}
+
+// installBundledCatalog uses the bundle's Composer versions manifest when
+// there is no cached one, or the cached one doesn't know the bundled
+// releases
+func installBundledCatalog(staging string, composerVersions []string) {
+	manifestPath, err := composerManifestPath()
+	if err != nil {
+		return
+	}
+	bundled := filepath.Join(staging, filepath.Base(manifestPath))
+	if _, err := os.Stat(bundled); err != nil {
+		return
+	}
+
+	if _, err := os.Stat(manifestPath); err == nil {
+		catalog := composerCatalog()
+		known := true
+		for _, version := range composerVersions {
+			if !slices.ContainsFunc(catalog, func(c data.ComposerVersion) bool { return c.Version == version }) {
+				known = false
+			}
+		}
+		if known {
+			return
+		}
+	}
+	if err := os.Rename(bundled, manifestPath); err != nil {
+		fmt.Printf("⚠️  Warning: Failed to install the Composer versions manifest: %v\n", err)
+	}
+}
+
+// installBundledVersion moves a PHP version from an unpacked bundle into
+// ~/.phpvm/versions, rewriting paths into the bundling machine's version
+// directory, and links its Composer
+func installBundledVersion(staging, bundleRoot string, entry BundleVersion) error {
+	installDir, err := versionDir(entry.Version)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(installDir), 0755); err != nil {
+		return fmt.Errorf("failed to create versions directory: %v", err)
+	}
+	if err := clearPartialInstall(installDir); err != nil {
+		return err
+	}
+	if err := os.Rename(filepath.Join(staging, "versions", entry.Version), installDir); err != nil {
+		return fmt.Errorf("failed to install PHP %s: %v", entry.Version, err)
+	}
+
+	oldDir := filepath.Join(bundleRoot, "versions", entry.Version)
+	if oldDir != installDir {
+		if err := rewriteVersionPaths(entry.Version, oldDir); err != nil {
+			fmt.Printf("⚠️  Warning: %v\n", err)
+		}
+	}
+	if err := ensureINI(entry.Version, defaultINIPreset); err != nil {
+		fmt.Printf("⚠️  Warning: Failed to create php.ini: %v\n", err)
+	}
+	fmt.Printf("✅ PHP %s installed to %s\n", entry.Version, installDir)
+
+	// Link the Composer the version used when it was bundled, which is
+	// present even when the local catalog prefers a newer release
+	if entry.Composer != "" {
+		if pharPath, err := composerPharPath(entry.Composer); err == nil {
+			if _, err := os.Stat(pharPath); err == nil {
+				if err := writeComposerWrapper(filepath.Join(installDir, "composer"), entry.Version, pharPath); err != nil {
+					return fmt.Errorf("failed to create Composer script: %v", err)
+				}
+				fmt.Printf("Composer %s linked to PHP %s\n", entry.Composer, entry.Version)
+				return nil
+			}
+		}
+	}
+	if err := installComposer(entry.Version, installDir); err != nil {
+		fmt.Printf("⚠️  Warning: Failed to install Composer: %v\n", err)
+	}
+	return nil
+}
+
+// rewriteVersionPaths replaces oldDir with a version's directory in its
+// php.ini, conf.d files and extension metadata
+func rewriteVersionPaths(version, oldDir string) error {
+	installDir, err := installedVersionDir(version)
+	if err != nil {
+		return err
+	}
+	rewrite := func(s string) string {
+		return strings.ReplaceAll(s, oldDir+string(filepath.Separator), installDir+string(filepath.Separator))
+	}
+
+	iniFiles, _ := filepath.Glob(filepath.Join(installDir, "conf.d", "*.ini"))
+	iniFiles = append(iniFiles, filepath.Join(installDir, "php.ini"))
+	for _, path := range iniFiles {
+		content, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %v", path, err)
+		}
+		if err := os.WriteFile(path, []byte(rewrite(string(content))), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %v", path, err)
+		}
+	}
+
+	meta, err := loadMetadata(version)
+	if err != nil {
+		return err
+	}
+	for name, state := range meta.Extensions {
+		state.Path = rewrite(state.Path)
+		meta.Extensions[name] = state
+	}
+	meta.Modules = nil
+	return saveMetadata(meta)
+}
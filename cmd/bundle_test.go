@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeBundleDir lays out an unpacked bundle with a manifest and SHA256SUMS
+// covering files and links
+func writeBundleDir(t *testing.T, files, links map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	manifest := BundleManifest{Format: 1, Versions: []BundleVersion{{Version: "8.3.9"}}, Links: links}
+	content, err := json.Marshal(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var sums strings.Builder
+	sums.WriteString(sha256Bytes(content) + "  " + bundleManifestFile + "\n")
+	write := func(name, body string) {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(body), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(bundleManifestFile, string(content))
+	for name, body := range files {
+		write(name, body)
+		sums.WriteString(sha256Bytes([]byte(body)) + "  " + name + "\n")
+	}
+	for name, target := range links {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Symlink(target, path); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(bundleChecksumsFile, sums.String())
+	return dir
+}
+
+func TestVerifyBundle(t *testing.T) {
+	files := map[string]string{
+		"versions/8.3.9/bin/php":  "php",
+		"versions/8.3.9/php.ini":  "memory_limit = 1G\n",
+		"composer/2.8.11/version": "2.8.11",
+	}
+	links := map[string]string{"versions/8.3.9/php": "bin/php"}
+
+	tests := []struct {
+		name    string
+		change  func(t *testing.T, dir string)
+		links   map[string]string
+		wantErr string
+	}{
+		{name: "intact"},
+		{
+			name: "unlisted file",
+			change: func(t *testing.T, dir string) {
+				os.WriteFile(filepath.Join(dir, "versions", "8.3.9", "extra"), []byte("x"), 0644)
+			},
+			wantErr: "isn't listed",
+		},
+		{
+			name: "unlisted symlink",
+			change: func(t *testing.T, dir string) {
+				os.Symlink("bin/php", filepath.Join(dir, "versions", "8.3.9", "php-cli"))
+			},
+			wantErr: "isn't listed",
+		},
+		{
+			name: "retargeted symlink",
+			change: func(t *testing.T, dir string) {
+				link := filepath.Join(dir, "versions", "8.3.9", "php")
+				os.Remove(link)
+				os.Symlink("php.ini", link)
+			},
+			wantErr: "doesn't point at",
+		},
+		{
+			name: "modified file",
+			change: func(t *testing.T, dir string) {
+				os.WriteFile(filepath.Join(dir, "versions", "8.3.9", "php.ini"), []byte("evil"), 0644)
+			},
+			wantErr: "checksum",
+		},
+		{
+			name:    "symlink leaving its version",
+			links:   map[string]string{"versions/8.3.9/php": "bin/php", "versions/8.3.9/state": "../../composer"},
+			wantErr: "outside its PHP version",
+		},
+		{
+			name:    "symlink outside any version",
+			links:   map[string]string{"versions/8.3.9/php": "bin/php", "composer/2.8.11/link": "version"},
+			wantErr: "outside its PHP version",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bundleLinks := links
+			if tt.links != nil {
+				bundleLinks = tt.links
+			}
+			dir := writeBundleDir(t, files, bundleLinks)
+			if tt.change != nil {
+				tt.change(t, dir)
+			}
+
+			manifest, err := verifyBundle(dir)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("verifyBundle() error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("verifyBundle() failed: %v", err)
+			}
+			if len(manifest.Versions) != 1 || manifest.Versions[0].Version != "8.3.9" {
+				t.Errorf("verifyBundle() manifest = %+v", manifest)
+			}
+		})
+	}
+}
+
+func TestCheckBundledName(t *testing.T) {
+	for _, name := range []string{"8.3.9", "8.5.0RC2", "nightly"} {
+		if err := checkBundledName(name); err != nil {
+			t.Errorf("checkBundledName(%q) = %v, want nil", name, err)
+		}
+	}
+	for _, name := range []string{"", ".", "..", "../8.3", "8.3/../..", "8.3-/../x", ".hidden", "custom"} {
+		if err := checkBundledName(name); err == nil {
+			t.Errorf("checkBundledName(%q) = nil, want an error", name)
+		}
+	}
+}
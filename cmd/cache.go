@@ -0,0 +1,482 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var cacheOlderThanFlag string
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the download cache",
+	Long: `PHP binaries, source tarballs, extensions and Composer phars are downloaded
+into a cache before they are hardlinked (or copied) into place, so
+reinstalling them doesn't download them again. Files are stored by their
+SHA-256. The cache lives in ~/.phpvm/cache; set PHPVM_CACHE_DIR to share one
+between users or CI containers. A shared cache directory must be writable by
+the group of users sharing it: phpvm creates its subdirectories group-writable
+and setgid so they stay that way. When the cache can't be used, files are
+downloaded directly.`,
+}
+
+var cacheListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List cached downloads",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return listCache()
+	},
+}
+
+var cacheCleanCmd = &cobra.Command{
+	Use:   "clean",
+	Short: "Remove every cached download",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return pruneCache(0)
+	},
+}
+
+var cachePruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove cached downloads that haven't been used recently",
+	Long: `Remove cached downloads that haven't been used for --older-than (30 days by
+default). Durations take a d suffix for days, e.g. 7d, or Go duration units
+such as 12h.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		age, err := parseAge(cacheOlderThanFlag)
+		if err != nil {
+			return err
+		}
+		return pruneCache(age)
+	},
+}
+
+func init() {
+	cachePruneCmd.Flags().StringVar(&cacheOlderThanFlag, "older-than", "30d", "remove downloads last used longer ago than this")
+	cacheCmd.AddCommand(cacheListCmd, cacheCleanCmd, cachePruneCmd)
+	RootCmd.AddCommand(cacheCmd)
+}
+
+// CacheEntry records a cached download: the URL it came from and the
+// SHA-256 of its content, which names the stored file. Downloads from URLs
+// whose content changes over time are recorded under a key instead.
+type CacheEntry struct {
+	URL          string    `json:"url"`
+	Key          string    `json:"key,omitempty"`
+	SHA256       string    `json:"sha256"`
+	Size         int64     `json:"size"`
+	DownloadedAt time.Time `json:"downloaded_at"`
+	LastUsed     time.Time `json:"last_used"`
+}
+
+// cacheDir returns the download cache directory
+func cacheDir() (string, error) {
+	if dir := os.Getenv("PHPVM_CACHE_DIR"); dir != "" {
+		return dir, nil
+	}
+	root, err := phpvmHome()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(root, "cache"), nil
+}
+
+// cacheBlobPath returns where content with a SHA-256 is stored
+func cacheBlobPath(dir, sum string) string {
+	return filepath.Join(dir, "sha256", sum[:2], sum)
+}
+
+// cacheKey returns what an entry is looked up by
+func (e *CacheEntry) cacheKey() string {
+	if e.Key != "" {
+		return e.Key
+	}
+	return e.URL
+}
+
+// cacheEntryPath returns the file recording which content a URL (or key)
+// has
+func cacheEntryPath(dir, key string) string {
+	return filepath.Join(dir, "urls", sha256Bytes([]byte(key))+".json")
+}
+
+// cachedDownload places the content of url at path, downloading it into the
+// cache first unless a verified copy is already there
+func cachedDownload(url, path string) error {
+	return cachedDownloadAs(url, "", path)
+}
+
+// cachedDownloadAs is cachedDownload for URLs that serve different content
+// over time, such as the per-branch PHP binaries; key names the content
+// wanted, e.g. "php-8.4.1-linux-amd64". A cache that can't be used, such as
+// a shared one without write access, is skipped with a warning.
+func cachedDownloadAs(url, key, path string) error {
+	err := downloadViaCache(url, key, path)
+	if _, ok := err.(*fetchError); err == nil || ok {
+		return err
+	}
+	fmt.Printf("⚠️  Warning: Not using the download cache: %v\n", err)
+	if _, err := os.Lstat(path); err == nil {
+		// Never write through a hardlink into the cache
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("failed to replace %s: %v", path, err)
+		}
+	}
+	return downloadFile(url, path)
+}
+
+// fetchError is a download failure, as opposed to a problem with the cache
+type fetchError struct {
+	err error
+}
+
+func (e *fetchError) Error() string {
+	return e.err.Error()
+}
+
+func downloadViaCache(url, key, path string) error {
+	dir, err := cacheDir()
+	if err != nil {
+		return err
+	}
+
+	lookup := key
+	if lookup == "" {
+		lookup = url
+	}
+	entry, err := readCacheEntry(cacheEntryPath(dir, lookup))
+	if err == nil {
+		blob := cacheBlobPath(dir, entry.SHA256)
+		if verifySHA256(blob, entry.SHA256) == nil {
+			fmt.Printf("Using cached %s\n", filepath.Base(url))
+			entry.LastUsed = time.Now().UTC()
+			if err := writeCacheEntry(dir, entry); err != nil {
+				fmt.Printf("⚠️  Warning: %v\n", err)
+			}
+			return linkFromCache(blob, path)
+		}
+		// A damaged copy is downloaded again
+		os.Remove(blob)
+	}
+
+	if entry, err = downloadToCache(dir, url, key); err != nil {
+		return err
+	}
+	return linkFromCache(cacheBlobPath(dir, entry.SHA256), path)
+}
+
+// makeCacheDir creates path inside the cache directory dir. In a cache
+// shared through PHPVM_CACHE_DIR, new directories are group-writable and
+// setgid, so every user in the group can add to them.
+func makeCacheDir(dir, path string) error {
+	if os.Getenv("PHPVM_CACHE_DIR") == "" {
+		if err := os.MkdirAll(path, 0755); err != nil {
+			return fmt.Errorf("failed to create cache directory: %v", err)
+		}
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dir), 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %v", err)
+	}
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return err
+	}
+	current := dir
+	for _, part := range append([]string{"."}, strings.Split(rel, string(filepath.Separator))...) {
+		current = filepath.Join(current, part)
+		err := os.Mkdir(current, 0775)
+		if os.IsExist(err) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to create cache directory: %v", err)
+		}
+		// Mkdir ignores the setgid bit and applies the umask
+		if err := os.Chmod(current, 0775|os.ModeSetgid); err != nil {
+			return fmt.Errorf("failed to create cache directory: %v", err)
+		}
+	}
+	return nil
+}
+
+// downloadToCache downloads url into the cache, hashing it on the way
+func downloadToCache(dir, url, key string) (*CacheEntry, error) {
+	tmpDir := filepath.Join(dir, "tmp")
+	if err := makeCacheDir(dir, tmpDir); err != nil {
+		return nil, err
+	}
+
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, &fetchError{err}
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, &fetchError{fmt.Errorf("bad status: %s", resp.Status)}
+	}
+
+	tmp, err := os.CreateTemp(tmpDir, "download-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cache file: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	h := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, h), resp.Body)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, &fetchError{err}
+	}
+
+	sum := hex.EncodeToString(h.Sum(nil))
+	blob := cacheBlobPath(dir, sum)
+	if err := makeCacheDir(dir, filepath.Dir(blob)); err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return nil, fmt.Errorf("failed to store download: %v", err)
+	}
+	if err := os.Rename(tmp.Name(), blob); err != nil {
+		return nil, fmt.Errorf("failed to store download: %v", err)
+	}
+
+	now := time.Now().UTC()
+	entry := &CacheEntry{URL: url, Key: key, SHA256: sum, Size: size, DownloadedAt: now, LastUsed: now}
+	if err := writeCacheEntry(dir, entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// linkFromCache hardlinks a cached file to path, copying it when the cache
+// is on another filesystem or the file belongs to another user, whose inode
+// the caller couldn't chmod
+func linkFromCache(blob, path string) error {
+	if _, err := os.Lstat(path); err == nil {
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("failed to replace %s: %v", path, err)
+		}
+	}
+	if info, err := os.Stat(blob); err == nil {
+		if stat, ok := info.Sys().(*syscall.Stat_t); ok && int(stat.Uid) == os.Getuid() {
+			if err := os.Link(blob, path); err == nil {
+				return nil
+			}
+		}
+	}
+	return copyFile(blob, path, 0644)
+}
+
+func readCacheEntry(path string) (*CacheEntry, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	entry := &CacheEntry{}
+	if err := json.Unmarshal(content, entry); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", path, err)
+	}
+	if len(entry.SHA256) != sha256.Size*2 {
+		return nil, fmt.Errorf("%s has no valid checksum", path)
+	}
+	return entry, nil
+}
+
+// writeCacheEntry records a URL's entry, replacing it atomically since the
+// cache can be shared by concurrent installs
+func writeCacheEntry(dir string, entry *CacheEntry) error {
+	path := cacheEntryPath(dir, entry.cacheKey())
+	if err := makeCacheDir(dir, filepath.Dir(path)); err != nil {
+		return err
+	}
+	content, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode cache entry: %v", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".entry-")
+	if err != nil {
+		return fmt.Errorf("failed to write cache entry: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(content, '\n')); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write cache entry: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write cache entry: %v", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return fmt.Errorf("failed to write cache entry: %v", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write cache entry: %v", err)
+	}
+	return nil
+}
+
+// cacheEntries returns every URL entry in the cache, most recently used
+// first
+func cacheEntries(dir string) ([]*CacheEntry, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "urls", "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	var entries []*CacheEntry
+	for _, path := range paths {
+		entry, err := readCacheEntry(path)
+		if err != nil {
+			fmt.Printf("⚠️  Warning: %v\n", err)
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].LastUsed.After(entries[j].LastUsed)
+	})
+	return entries, nil
+}
+
+func listCache() error {
+	dir, err := cacheDir()
+	if err != nil {
+		return err
+	}
+	entries, err := cacheEntries(dir)
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		fmt.Printf("The download cache (%s) is empty\n", dir)
+		return nil
+	}
+
+	fmt.Printf("%-10s %-12s %-14s %s\n", "Size", "Last used", "SHA-256", "URL")
+	fmt.Println("----------------------------------------------------------------------")
+	var total int64
+	counted := map[string]bool{}
+	for _, entry := range entries {
+		source := entry.URL
+		if entry.Key != "" {
+			source += " (" + entry.Key + ")"
+		}
+		fmt.Printf("%-10s %-12s %-14s %s\n", formatSize(entry.Size), entry.LastUsed.Local().Format("2006-01-02"), entry.SHA256[:12], source)
+		// Identical content downloaded from two URLs is stored once
+		if !counted[entry.SHA256] {
+			counted[entry.SHA256] = true
+			total += entry.Size
+		}
+	}
+	fmt.Printf("\n%d download(s), %s in %s\n", len(entries), formatSize(total), dir)
+	return nil
+}
+
+// pruneCache removes the entries not used within maxAge, then any stored
+// content no entry refers to and abandoned partial downloads. A zero maxAge
+// empties the cache, apart from downloads still in progress.
+func pruneCache(maxAge time.Duration) error {
+	dir, err := cacheDir()
+	if err != nil {
+		return err
+	}
+	entries, err := cacheEntries(dir)
+	if err != nil {
+		return err
+	}
+
+	cutoff := time.Now().Add(-maxAge)
+	kept := map[string]bool{}
+	removed := 0
+	for _, entry := range entries {
+		if maxAge > 0 && entry.LastUsed.After(cutoff) {
+			kept[entry.SHA256] = true
+			continue
+		}
+		if err := os.Remove(cacheEntryPath(dir, entry.cacheKey())); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove cache entry for %s: %v", entry.URL, err)
+		}
+		removed++
+	}
+
+	var freed int64
+	blobs, _ := filepath.Glob(filepath.Join(dir, "sha256", "*", "*"))
+	for _, blob := range blobs {
+		if kept[filepath.Base(blob)] {
+			continue
+		}
+		info, err := os.Stat(blob)
+		if err != nil {
+			continue
+		}
+		if err := os.Remove(blob); err != nil {
+			return fmt.Errorf("failed to remove %s: %v", blob, err)
+		}
+		freed += info.Size()
+	}
+
+	// Downloads in progress keep touching their temporary file, so only
+	// leftovers that haven't been written to for an hour are removed
+	tmpCutoff := cutoff
+	if maxAge < time.Hour {
+		tmpCutoff = time.Now().Add(-time.Hour)
+	}
+	leftovers, _ := filepath.Glob(filepath.Join(dir, "tmp", "download-*"))
+	for _, tmp := range leftovers {
+		if info, err := os.Stat(tmp); err == nil && info.ModTime().Before(tmpCutoff) {
+			os.Remove(tmp)
+		}
+	}
+
+	fmt.Printf("✅ Removed %d download(s), freeing %s\n", removed, formatSize(freed))
+	return nil
+}
+
+// parseAge parses a duration that may use a d suffix for days
+func parseAge(value string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	age, err := time.ParseDuration(value)
+	if err != nil || age <= 0 {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+	return age, nil
+}
+
+// formatSize renders a byte count for humans
+func formatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
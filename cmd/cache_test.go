@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// serveContent starts a server answering every request with content and
+// counting the requests
+func serveContent(t *testing.T, content string) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Write([]byte(content))
+	}))
+	t.Cleanup(server.Close)
+	return server, &hits
+}
+
+func TestCachedDownloadLinksFromCache(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	cache := filepath.Join(t.TempDir(), "cache")
+	t.Setenv("PHPVM_CACHE_DIR", cache)
+	server, hits := serveContent(t, "composer phar")
+	dest := t.TempDir()
+
+	first := filepath.Join(dest, "first")
+	if err := cachedDownload(server.URL+"/composer.phar", first); err != nil {
+		t.Fatalf("cachedDownload() failed: %v", err)
+	}
+	second := filepath.Join(dest, "second")
+	if err := cachedDownload(server.URL+"/composer.phar", second); err != nil {
+		t.Fatalf("cachedDownload() failed: %v", err)
+	}
+	if n := hits.Load(); n != 1 {
+		t.Errorf("server got %d requests, want 1", n)
+	}
+
+	blob := cacheBlobPath(cache, sha256Bytes([]byte("composer phar")))
+	blobInfo, err := os.Stat(blob)
+	if err != nil {
+		t.Fatalf("cached blob missing: %v", err)
+	}
+	for _, path := range []string{first, second} {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !os.SameFile(info, blobInfo) {
+			t.Errorf("%s isn't hardlinked to the cached blob", filepath.Base(path))
+		}
+	}
+
+	// A shared cache gets group-writable, setgid directories
+	info, err := os.Stat(filepath.Dir(blob))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode()&os.ModeSetgid == 0 || info.Mode().Perm()&0020 == 0 {
+		t.Errorf("cache directory mode = %v, want group-writable and setgid", info.Mode())
+	}
+}
+
+func TestLinkFromCacheCopiesOtherUsersFiles(t *testing.T) {
+	if os.Getuid() != 0 {
+		t.Skip("changing a file's owner needs root")
+	}
+	dir := t.TempDir()
+	blob := filepath.Join(dir, "blob")
+	if err := os.WriteFile(blob, []byte("php"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chown(blob, 65534, -1); err != nil {
+		t.Skipf("can't change owner: %v", err)
+	}
+
+	path := filepath.Join(dir, "php")
+	if err := linkFromCache(blob, path); err != nil {
+		t.Fatalf("linkFromCache() failed: %v", err)
+	}
+	blobInfo, _ := os.Stat(blob)
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if os.SameFile(info, blobInfo) {
+		t.Error("another user's blob was hardlinked instead of copied")
+	}
+	if err := os.Chmod(path, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if blobInfo, _ := os.Stat(blob); blobInfo.Mode().Perm() != 0644 {
+		t.Errorf("blob mode changed to %v", blobInfo.Mode().Perm())
+	}
+}
+
+func TestCachedDownloadFallsBackWithoutCache(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	// A cache path that is a file can't be used
+	notDir := filepath.Join(t.TempDir(), "cache")
+	if err := os.WriteFile(notDir, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PHPVM_CACHE_DIR", notDir)
+	server, _ := serveContent(t, "php binary")
+
+	path := filepath.Join(t.TempDir(), "php")
+	if err := cachedDownload(server.URL+"/php", path); err != nil {
+		t.Fatalf("cachedDownload() failed: %v", err)
+	}
+	if content, err := os.ReadFile(path); err != nil || string(content) != "php binary" {
+		t.Errorf("downloaded %q, %v", content, err)
+	}
+}
+
+func TestPruneCacheKeepsDownloadsInProgress(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	cache := filepath.Join(t.TempDir(), "cache")
+	t.Setenv("PHPVM_CACHE_DIR", cache)
+	server, _ := serveContent(t, "extension")
+	if err := cachedDownload(server.URL+"/redis.tgz", filepath.Join(t.TempDir(), "redis.tgz")); err != nil {
+		t.Fatal(err)
+	}
+
+	tmp := filepath.Join(cache, "tmp")
+	active := filepath.Join(tmp, "download-active")
+	abandoned := filepath.Join(tmp, "download-abandoned")
+	for _, path := range []string{active, abandoned} {
+		if err := os.WriteFile(path, []byte("partial"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	old := time.Now().Add(-2 * time.Hour)
+	if err := os.Chtimes(abandoned, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := pruneCache(0); err != nil {
+		t.Fatalf("pruneCache() failed: %v", err)
+	}
+	if _, err := os.Stat(active); err != nil {
+		t.Errorf("an in-progress download was removed: %v", err)
+	}
+	if _, err := os.Stat(abandoned); !os.IsNotExist(err) {
+		t.Errorf("an abandoned download was kept")
+	}
+	if blobs, _ := filepath.Glob(filepath.Join(cache, "sha256", "*", "*")); len(blobs) != 0 {
+		t.Errorf("pruneCache(0) kept %v", blobs)
+	}
+}
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/spf13/cobra"
+	"github.com/yourusername/phpvm/data"
+)
+
+var checkStrictFlag bool
+
+var checkCmd = &cobra.Command{
+	Use:   "check [version]",
+	Short: "Check a PHP version against the project's composer.json",
+	Long: `Compare a PHP version (the active one by default) with the nearest
+composer.json: require.php must match the version and every ext-* requirement
+must be provided by the binary. With --strict, unmet requirements make the
+command exit with a non-zero status.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		flagValue := ""
+		if len(args) == 1 {
+			flagValue = args[0]
+		}
+		version, err := targetVersion(flagValue)
+		if err != nil {
+			return err
+		}
+		if _, err := installedVersionDir(version); err != nil {
+			return err
+		}
+		return checkProject(version, checkStrictFlag, true)
+	},
+}
+
+func init() {
+	checkCmd.Flags().BoolVar(&checkStrictFlag, "strict", false, "exit with an error when requirements are not met")
+	RootCmd.AddCommand(checkCmd)
+}
+
+// projectProblems compares a PHP version with the requirements in a
+// composer.json and describes every requirement that isn't met
+func projectProblems(version string, composer *ComposerJSON) []string {
+	var problems []string
+
+	if constraint := composer.PHPConstraint(); constraint != "" {
+		ok, err := data.SatisfiesConstraint(version, constraint)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("cannot parse require.php %q: %v", constraint, err))
+		} else if !ok {
+			problems = append(problems, fmt.Sprintf("PHP %s does not satisfy require.php %q", version, constraint))
+		}
+	}
+
+	required := composer.RequiredExtensions()
+	if len(required) == 0 {
+		return problems
+	}
+
+	scan, err := moduleScan(version, false)
+	if err != nil {
+		return append(problems, fmt.Sprintf("could not inspect the extensions of PHP %s: %v", version, err))
+	}
+	for _, ext := range required {
+		if !slices.Contains(scan.Loaded, ext) {
+			problems = append(problems, fmt.Sprintf("missing extension %s (ext-%s)", ext, ext))
+		}
+	}
+	return problems
+}
+
+// checkProject reports whether the nearest composer.json accepts a PHP
+// version. Unmet requirements are warnings unless strict is set.
+func checkProject(version string, strict, verbose bool) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %v", err)
+	}
+
+	composer, err := findComposerJSON(cwd)
+	if err != nil {
+		return err
+	}
+	if composer == nil {
+		if verbose {
+			fmt.Println("ℹ️  No composer.json found, nothing to check")
+		}
+		return nil
+	}
+
+	problems := projectProblems(version, composer)
+	if len(problems) == 0 {
+		if verbose {
+			fmt.Printf("✅ PHP %s meets the requirements of %s\n", version, composer.Path)
+		}
+		return nil
+	}
+
+	fmt.Printf("⚠️  PHP %s does not meet the requirements of %s:\n", version, composer.Path)
+	for _, problem := range problems {
+		fmt.Printf("   - %s\n", problem)
+	}
+
+	if strict {
+		return fmt.Errorf("PHP %s does not meet the project requirements", version)
+	}
+	return nil
+}
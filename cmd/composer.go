@@ -0,0 +1,420 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/spf13/cobra"
+	"github.com/yourusername/phpvm/data"
+)
+
+var (
+	composerPHPFlag     string
+	composerProjectFlag bool
+	composerUnpinFlag   bool
+	composerRefreshFlag bool
+	composerChannelFlag string
+)
+
+var composerCmd = &cobra.Command{
+	Use:   "composer",
+	Short: "Manage Composer versions",
+	Long: `Manage the Composer versions used by each PHP version.
+
+By default every PHP version uses the newest Composer release compatible with
+it. A PHP version or a project directory can be pinned to a specific release
+with 'phpvm composer use'; project pins take precedence over version pins.`,
+}
+
+var composerListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List Composer versions",
+	Long: `List the Composer releases phpvm knows about, with their channel (stable,
+preview, lts for the 2.2 LTS line, or 1.x) and the oldest PHP they support.
+The list comes from getcomposer.org/versions and is cached for a day; set
+PHPVM_COMPOSER_MANIFEST to use a local manifest in the same format instead.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if composerRefreshFlag {
+			if err := refreshComposerCatalog(); err != nil {
+				return err
+			}
+		} else {
+			refreshComposerCatalogIfStale()
+		}
+		return listComposerVersions(composerChannelFlag)
+	},
+}
+
+var composerInstallCmd = &cobra.Command{
+	Use:   "install <version>",
+	Short: "Download a Composer version",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := checkComposerVersionName(args[0]); err != nil {
+			return err
+		}
+		refreshComposerCatalogIfStale()
+		if _, err := ensureComposerPhar(findComposerVersion(args[0])); err != nil {
+			return err
+		}
+		fmt.Printf("✅ Composer %s is installed\n", args[0])
+		return nil
+	},
+}
+
+var composerUseCmd = &cobra.Command{
+	Use:   "use [version]",
+	Short: "Pin a Composer version for a PHP version or project",
+	Long: `Pin the Composer version used with a PHP version (the resolved one, or
+--php), or with the current project directory when --project is given.
+Use --unpin to go back to the newest compatible release.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		pin := ""
+		if !composerUnpinFlag {
+			if len(args) != 1 {
+				return fmt.Errorf("a Composer version is required (or --unpin)")
+			}
+			pin = args[0]
+		}
+		return useComposerVersion(pin, composerPHPFlag, composerProjectFlag)
+	},
+}
+
+func init() {
+	composerListCmd.Flags().BoolVar(&composerRefreshFlag, "refresh", false, "fetch the latest versions from getcomposer.org")
+	composerListCmd.Flags().StringVar(&composerChannelFlag, "channel", "", "only show one channel (stable, preview, lts or 1.x)")
+	composerUseCmd.Flags().StringVar(&composerPHPFlag, "php", "", "PHP version to pin Composer for (defaults to the resolved version)")
+	composerUseCmd.Flags().BoolVar(&composerProjectFlag, "project", false, "pin Composer for the current project directory instead")
+	composerUseCmd.Flags().BoolVar(&composerUnpinFlag, "unpin", false, "remove the pin")
+
+	composerCmd.AddCommand(composerListCmd, composerInstallCmd, composerUseCmd)
+	RootCmd.AddCommand(composerCmd)
+}
+
+// composerPharPath returns where a Composer version's phar is stored
+func composerPharPath(version string) (string, error) {
+	root, err := phpvmHome()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(root, "composer", version, "composer.phar"), nil
+}
+
+// resolveComposerVersion picks the Composer version for a PHP version in the
+// current directory: a project pin, then the PHP version's pin, then the
+// newest compatible release. The second value describes the choice. Only
+// the Composer shim honours project pins; wrappers written to disk use
+// versionComposerVersion.
+func resolveComposerVersion(phpVersion string) (*data.ComposerVersion, string, error) {
+	if cwd, err := os.Getwd(); err == nil {
+		settings, projectDir, err := findProjectSettings(cwd)
+		if err != nil {
+			return nil, "", err
+		}
+		if settings.Composer != "" {
+			return findComposerVersion(settings.Composer), "pinned for " + projectDir, nil
+		}
+	}
+	return versionComposerVersion(phpVersion)
+}
+
+// activeComposerVersion picks the Composer version that runs for a PHP
+// version in the current directory, which honours project pins only in
+// shim mode
+func activeComposerVersion(phpVersion string) (*data.ComposerVersion, string, error) {
+	config, err := loadConfig()
+	if err != nil {
+		return nil, "", err
+	}
+	if config.ComposerShim {
+		return resolveComposerVersion(phpVersion)
+	}
+	return versionComposerVersion(phpVersion)
+}
+
+// versionComposerVersion picks the Composer version of a PHP version
+// regardless of the current directory: its pin, then the newest compatible
+// release
+func versionComposerVersion(phpVersion string) (*data.ComposerVersion, string, error) {
+	meta, err := loadMetadata(phpVersion)
+	if err != nil {
+		return nil, "", err
+	}
+	if meta.Composer != "" {
+		return findComposerVersion(meta.Composer), "pinned for PHP " + phpVersion, nil
+	}
+
+	composerVersion := data.GetCompatibleComposerVersion(composerCatalog(), phpVersion)
+	if composerVersion == nil {
+		return nil, "", fmt.Errorf("no compatible Composer version found for PHP %s", phpVersion)
+	}
+	return composerVersion, "newest compatible", nil
+}
+
+func listComposerVersions(channel string) error {
+	catalog := composerCatalog()
+	versions := map[string]*data.ComposerVersion{}
+	for i := range catalog {
+		versions[catalog[i].Version] = &catalog[i]
+	}
+
+	// Include releases downloaded outside the catalog, e.g. pinned 1.x versions
+	root, err := phpvmHome()
+	if err != nil {
+		return err
+	}
+	if entries, err := os.ReadDir(filepath.Join(root, "composer")); err == nil {
+		for _, entry := range entries {
+			if _, ok := versions[entry.Name()]; !ok && entry.IsDir() {
+				versions[entry.Name()] = findComposerVersion(entry.Name())
+			}
+		}
+	}
+
+	sorted := make([]string, 0, len(versions))
+	for version := range versions {
+		if channel == "" || versions[version].Channel == channel {
+			sorted = append(sorted, version)
+		}
+	}
+	data.SortVersions(sorted)
+	slices.Reverse(sorted)
+
+	current := ""
+	if phpVersion, err := targetVersion(""); err == nil {
+		if composerVersion, source, err := activeComposerVersion(phpVersion); err == nil {
+			current = composerVersion.Version
+			fmt.Printf("PHP %s uses Composer %s (%s)\n\n", phpVersion, current, source)
+		}
+	}
+
+	fmt.Println("Composer versions:")
+	fmt.Printf("%-12s %-9s %-9s %-12s\n", "Version", "Channel", "Min PHP", "Released")
+	fmt.Println("----------------------------------------------")
+	for _, version := range sorted {
+		composerVersion := versions[version]
+		status := " "
+		if path, err := composerPharPath(version); err == nil {
+			if _, err := os.Stat(path); err == nil {
+				status = "*"
+			}
+		}
+		if version == current {
+			status = ">"
+		}
+
+		released := "-"
+		if !composerVersion.Released.IsZero() {
+			released = composerVersion.Released.Format("2006-01-02")
+		}
+		fmt.Printf("%-12s %-9s %-9s %-12s\n", status+version,
+			valueOrDash(composerVersion.Channel),
+			valueOrDash(composerVersion.MinPHPVersion),
+			released)
+	}
+
+	fmt.Println("\n* = Installed, > = In use")
+	return nil
+}
+
+// valueOrDash returns s, or "-" when it is empty
+func valueOrDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// useComposerVersion pins (or unpins, when pin is empty) a Composer version
+// and regenerates the Composer wrappers affected by it
+func useComposerVersion(pin, phpFlag string, project bool) error {
+	phpVersion, err := targetVersion(phpFlag)
+	if err != nil {
+		return err
+	}
+
+	if pin != "" {
+		if err := checkComposerVersionName(pin); err != nil {
+			return err
+		}
+		if _, err := ensureComposerPhar(findComposerVersion(pin)); err != nil {
+			return err
+		}
+	}
+
+	if project {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get current directory: %v", err)
+		}
+		projects, err := loadProjects()
+		if err != nil {
+			return err
+		}
+		settings := projects[cwd]
+		settings.Composer = pin
+		if settings == (ProjectSettings{}) {
+			delete(projects, cwd)
+		} else {
+			projects[cwd] = settings
+		}
+		if err := saveProjects(projects); err != nil {
+			return err
+		}
+	} else {
+		meta, err := loadMetadata(phpVersion)
+		if err != nil {
+			return err
+		}
+		meta.Composer = pin
+		if err := saveMetadata(meta); err != nil {
+			return err
+		}
+	}
+
+	if err := relinkComposer(phpVersion); err != nil {
+		return err
+	}
+
+	target := "PHP " + phpVersion
+	if project {
+		target = "this project"
+	}
+	if pin == "" {
+		fmt.Printf("✅ Removed the Composer pin for %s\n", target)
+	} else {
+		fmt.Printf("✅ Composer %s pinned for %s\n", pin, target)
+	}
+	if project && pin != "" {
+		if config, err := loadConfig(); err == nil && !config.ComposerShim {
+			fmt.Println("ℹ️  Project pins apply through the Composer shim. Enable it with 'phpvm config composer.shim true'")
+		}
+	}
+	return nil
+}
+
+// relinkComposer regenerates the Composer wrappers of a PHP version, and the
+// global one when that version is active
+func relinkComposer(phpVersion string) error {
+	installDir, err := installedVersionDir(phpVersion)
+	if err != nil {
+		return err
+	}
+	if err := installComposer(phpVersion, installDir); err != nil {
+		return err
+	}
+
+	if active, err := activeVersion(); err == nil && active == phpVersion {
+		root, err := phpvmHome()
+		if err != nil {
+			return err
+		}
+		return createComposerSymlink(phpVersion, filepath.Join(root, "bin"))
+	}
+	return nil
+}
+
+// composerHome returns the isolated COMPOSER_HOME of a PHP version, or an
+// empty string when versions share the user's global Composer home
+func composerHome(phpVersion string) (string, error) {
+	config, err := loadConfig()
+	if err != nil {
+		return "", err
+	}
+	if !config.IsolatedComposerHome {
+		return "", nil
+	}
+
+	dir, err := versionDir(phpVersion)
+	if err != nil {
+		return "", err
+	}
+	home := filepath.Join(dir, "composer-home")
+	if err := os.MkdirAll(home, 0755); err != nil {
+		return "", fmt.Errorf("failed to create %s: %v", home, err)
+	}
+	return home, nil
+}
+
+// linkComposerHome points ~/.phpvm/composer-home at the active version's
+// COMPOSER_HOME, so the shell integration's PATH entry for its vendor/bin
+// follows switches.
+// With shared homes the link is removed.
+func linkComposerHome(phpVersion string) error {
+	root, err := phpvmHome()
+	if err != nil {
+		return err
+	}
+	link := filepath.Join(root, "composer-home")
+
+	// The system PHP uses the user's own Composer home
+	home := ""
+	if phpVersion != systemVersion {
+		if home, err = composerHome(phpVersion); err != nil {
+			return err
+		}
+	}
+
+	if _, err := os.Lstat(link); err == nil {
+		if err := os.Remove(link); err != nil {
+			return fmt.Errorf("failed to remove existing Composer home link: %v", err)
+		}
+	}
+	if home == "" {
+		return nil
+	}
+
+	if err := os.Symlink(home, link); err != nil {
+		return fmt.Errorf("failed to link Composer home: %v", err)
+	}
+	return nil
+}
+
+// composerVendorBin returns the vendor/bin directory of a PHP version's
+// isolated Composer home, for the shell integration to put on PATH. An
+// empty version means the global default, through ~/.phpvm/composer-home.
+// It returns an empty string when Composer homes are shared.
+func composerVendorBin(phpVersion string) (string, error) {
+	config, err := loadConfig()
+	if err != nil {
+		return "", err
+	}
+	if !config.IsolatedComposerHome || phpVersion == systemVersion {
+		return "", nil
+	}
+	root, err := phpvmHome()
+	if err != nil {
+		return "", err
+	}
+	if phpVersion == "" {
+		return filepath.Join(root, "composer-home", "vendor", "bin"), nil
+	}
+	dir, err := versionDir(phpVersion)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "composer-home", "vendor", "bin"), nil
+}
+
+// relinkAllComposers regenerates the Composer wrappers of every installed
+// version after a setting that affects them has changed
+func relinkAllComposers() error {
+	versions, err := installedVersions()
+	if err != nil {
+		return err
+	}
+	for _, version := range versions {
+		if err := relinkComposer(version); err != nil {
+			fmt.Printf("⚠️  Warning: Failed to relink Composer for PHP %s: %v\n", version, err)
+		}
+	}
+
+	if active, err := activeVersion(); err == nil {
+		return linkComposerHome(active)
+	}
+	return nil
+}
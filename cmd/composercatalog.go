@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/yourusername/phpvm/data"
+)
+
+// composerManifestMaxAge is how long a fetched versions manifest is used
+// before phpvm tries to refresh it
+const composerManifestMaxAge = 24 * time.Hour
+
+// composerManifestPath returns where the fetched versions manifest is cached
+func composerManifestPath() (string, error) {
+	root, err := phpvmHome()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(root, "composer-versions.json"), nil
+}
+
+// composerCatalog returns the known Composer releases. A manifest named by
+// PHPVM_COMPOSER_MANIFEST (a local stand-in for getcomposer.org/versions)
+// wins, then the cached manifest, then the built-in catalog.
+func composerCatalog() []data.ComposerVersion {
+	path := os.Getenv("PHPVM_COMPOSER_MANIFEST")
+	if path == "" {
+		cached, err := composerManifestPath()
+		if err != nil {
+			return data.AvailableComposerVersions
+		}
+		path = cached
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return data.AvailableComposerVersions
+	}
+	catalog, err := data.ParseComposerManifest(content)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Warning: Ignoring %s: %v\n", path, err)
+		return data.AvailableComposerVersions
+	}
+	return catalog
+}
+
+// findComposerVersion looks a Composer release up in the current catalog
+func findComposerVersion(version string) *data.ComposerVersion {
+	return data.FindComposerVersion(composerCatalog(), version)
+}
+
+// refreshComposerCatalog fetches the versions manifest from getcomposer.org
+// and caches it
+func refreshComposerCatalog() error {
+	resp, err := http.Get(data.ComposerVersionsURL)
+	if err != nil {
+		return fmt.Errorf("failed to fetch Composer versions: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch Composer versions: bad status: %s", resp.Status)
+	}
+
+	content, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("failed to fetch Composer versions: %v", err)
+	}
+	if _, err := data.ParseComposerManifest(content); err != nil {
+		return err
+	}
+
+	path, err := composerManifestPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %v", filepath.Dir(path), err)
+	}
+	// Parallel installs may refresh at the same time, so readers must never
+	// see a partly written manifest
+	tmp := fmt.Sprintf("%s.%d", path, os.Getpid())
+	if err := os.WriteFile(tmp, content, 0644); err != nil {
+		return fmt.Errorf("failed to cache Composer versions: %v", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to cache Composer versions: %v", err)
+	}
+	return nil
+}
+
+// refreshComposerCatalogIfStale refreshes the cached manifest when it is
+// missing or older than a day. Failures are ignored so that phpvm keeps
+// working offline with what it already knows.
+func refreshComposerCatalogIfStale() {
+	if os.Getenv("PHPVM_COMPOSER_MANIFEST") != "" {
+		return
+	}
+	path, err := composerManifestPath()
+	if err != nil {
+		return
+	}
+	if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) < composerManifestMaxAge {
+		return
+	}
+	_ = refreshComposerCatalog()
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ComposerJSON holds the parts of a project's composer.json phpvm cares about
+type ComposerJSON struct {
+	Path    string            `json:"-"`
+	Require map[string]string `json:"require"`
+	Config  struct {
+		Platform map[string]string `json:"platform"`
+	} `json:"config"`
+}
+
+// findUpwards returns the first file with the given name in dir or any of
+// its parents
+func findUpwards(dir, name string) (string, bool) {
+	for {
+		candidate := filepath.Join(dir, name)
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate, true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// findComposerJSON loads the composer.json nearest to dir, returning nil when
+// the directory isn't inside a Composer project
+func findComposerJSON(dir string) (*ComposerJSON, error) {
+	path, found := findUpwards(dir, "composer.json")
+	if !found {
+		return nil, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", path, err)
+	}
+
+	composer := &ComposerJSON{Path: path}
+	if err := json.Unmarshal(content, composer); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", path, err)
+	}
+	return composer, nil
+}
+
+// PHPConstraint returns the require.php constraint, if any
+func (c *ComposerJSON) PHPConstraint() string {
+	return strings.TrimSpace(c.Require["php"])
+}
+
+// RequiredExtensions returns the ext-* requirements as extension names
+func (c *ComposerJSON) RequiredExtensions() []string {
+	var extensions []string
+	for pkg := range c.Require {
+		if name, ok := strings.CutPrefix(strings.ToLower(pkg), "ext-"); ok {
+			extensions = append(extensions, composerExtensionName(name))
+		}
+	}
+	sort.Strings(extensions)
+	return extensions
+}
+
+// composerExtensionName maps Composer's ext-* package names onto the
+// extension names reported by php -m
+func composerExtensionName(name string) string {
+	switch name {
+	case "zend-opcache":
+		return "opcache"
+	}
+	return strings.ReplaceAll(name, "-", "_")
+}
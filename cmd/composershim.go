@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	"github.com/spf13/cobra"
+)
+
+var composerShimCmd = &cobra.Command{
+	Use:   "composer-shim [composer arguments]",
+	Short: "Run Composer with the PHP and Composer versions resolved for this directory",
+	Long: `Run Composer with the PHP version resolved for the current directory and
+the Composer version pinned for it. This is what ~/.phpvm/bin/composer runs
+when the composer.shim setting is enabled; all arguments go to Composer.`,
+	Hidden:             true,
+	DisableFlagParsing: true,
+	SilenceUsage:       true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runComposerShim(args)
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(composerShimCmd)
+}
+
+func runComposerShim(args []string) error {
+	phpVersion, err := targetVersion("")
+	if err != nil {
+		return err
+	}
+
+	composerVersion, _, err := resolveComposerVersion(phpVersion)
+	if err != nil {
+		return err
+	}
+
+	pharPath, err := composerPharPath(composerVersion.Version)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(pharPath); err != nil {
+		return fmt.Errorf("Composer %s is not installed. Use 'phpvm composer install %s' first", composerVersion.Version, composerVersion.Version)
+	}
+
+	env, err := composerEnv(phpVersion)
+	if err != nil {
+		return err
+	}
+	installDir, err := installedVersionDir(phpVersion)
+	if err != nil {
+		return err
+	}
+
+	php := filepath.Join(installDir, "php")
+	argv := append([]string{php, pharPath}, args...)
+	if err := syscall.Exec(php, argv, envList(env)); err != nil {
+		return fmt.Errorf("failed to run Composer: %v", err)
+	}
+	return nil
+}
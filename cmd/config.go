@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+// Config holds phpvm's user settings
+type Config struct {
+	IsolatedComposerHome bool `json:"composer_isolated_home,omitempty"`
+	ComposerShim         bool `json:"composer_shim,omitempty"`
+}
+
+// configKeys maps the keys accepted by 'phpvm config' to their fields
+var configKeys = map[string]func(c *Config) *bool{
+	"composer.isolated-home": func(c *Config) *bool { return &c.IsolatedComposerHome },
+	"composer.shim":          func(c *Config) *bool { return &c.ComposerShim },
+}
+
+// configHooks run after a key has been changed
+var configHooks = map[string]func() error{
+	"composer.isolated-home": relinkAllComposers,
+	"composer.shim":          relinkAllComposers,
+}
+
+var configCmd = &cobra.Command{
+	Use:   "config [key] [value]",
+	Short: "Show or change phpvm settings",
+	Long: `Show or change phpvm settings. Without arguments all settings are listed.
+
+Settings:
+  composer.isolated-home  give each PHP version its own COMPOSER_HOME under
+                          ~/.phpvm/versions/<version>/composer-home
+  composer.shim           make ~/.phpvm/bin/composer run 'phpvm composer-shim',
+                          which resolves the PHP and Composer versions (including
+                          project pins) every time it runs`,
+	Args: cobra.MaximumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		switch len(args) {
+		case 0:
+			return showConfig("")
+		case 1:
+			return showConfig(args[0])
+		}
+		return setConfig(args[0], args[1])
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(configCmd)
+}
+
+// loadConfig reads ~/.phpvm/config.json, returning defaults when it's missing
+func loadConfig() (*Config, error) {
+	root, err := phpvmHome()
+	if err != nil {
+		return nil, err
+	}
+
+	config := &Config{}
+	content, err := os.ReadFile(filepath.Join(root, "config.json"))
+	if os.IsNotExist(err) {
+		return config, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %v", err)
+	}
+	if err := json.Unmarshal(content, config); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %v", err)
+	}
+	return config, nil
+}
+
+// saveConfig writes ~/.phpvm/config.json
+func saveConfig(config *Config) error {
+	root, err := phpvmHome()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %v", root, err)
+	}
+
+	content, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode config: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "config.json"), append(content, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write config: %v", err)
+	}
+	return nil
+}
+
+func showConfig(key string) error {
+	config, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	if key != "" {
+		field, ok := configKeys[key]
+		if !ok {
+			return fmt.Errorf("unknown setting %q", key)
+		}
+		fmt.Println(*field(config))
+		return nil
+	}
+
+	keys := make([]string, 0, len(configKeys))
+	for k := range configKeys {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Printf("%s = %t\n", k, *configKeys[k](config))
+	}
+	return nil
+}
+
+func setConfig(key, value string) error {
+	field, ok := configKeys[key]
+	if !ok {
+		return fmt.Errorf("unknown setting %q", key)
+	}
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		return fmt.Errorf("invalid value %q for %s (expected true or false)", value, key)
+	}
+
+	config, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	*field(config) = enabled
+	if err := saveConfig(config); err != nil {
+		return err
+	}
+	fmt.Printf("✅ %s = %t\n", key, enabled)
+
+	if hook, ok := configHooks[key]; ok {
+		return hook()
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var currentShortFlag bool
+
+var currentCmd = &cobra.Command{
+	Use:   "current",
+	Short: "Show the PHP version phpvm selects here and why",
+	Long: `Show the PHP version phpvm resolves for the current directory, what set
+it (a 'phpvm shell' session, a project file, an alias or the global default)
+and the binary it runs. Nothing is executed. A warning is shown when the php
+found first on PATH isn't the one phpvm selected.`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return showCurrent(currentShortFlag)
+	},
+}
+
+func init() {
+	currentCmd.Flags().BoolVar(&currentShortFlag, "short", false, "print only the version")
+	RootCmd.AddCommand(currentCmd)
+}
+
+func showCurrent(short bool) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %v", err)
+	}
+	resolution, err := resolveVersion(cwd)
+	if err != nil {
+		return err
+	}
+	if resolution.Version == "" {
+		return fmt.Errorf("%s asks for PHP %s, which isn't installed", resolution.Source, resolution.Request)
+	}
+
+	if short {
+		fmt.Println(resolution.Version)
+		return nil
+	}
+	if resolution.Version == systemVersion {
+		return showCurrentSystem(resolution)
+	}
+
+	installDir, err := installedVersionDir(resolution.Version)
+	if err != nil {
+		return err
+	}
+	binary := filepath.Join(installDir, "php")
+	if resolved, err := filepath.EvalSymlinks(binary); err == nil {
+		binary = resolved
+	}
+
+	fmt.Printf("PHP %s\n", resolution.Version)
+	fmt.Printf("  set by:  %s\n", describeSource(resolution))
+	if resolution.Alias != "" {
+		fmt.Printf("  alias:   %s -> %s\n", resolution.Alias, resolution.Request)
+	} else if resolution.Request != resolution.Version {
+		fmt.Printf("  request: %s\n", resolution.Request)
+	}
+	fmt.Printf("  binary:  %s\n", binary)
+	warnIfEOL(resolution.Version)
+
+	pathVersion, pathBinary := pathPHPVersion()
+	switch {
+	case pathBinary == "":
+		fmt.Println("⚠️  Warning: No php found on your PATH. Run 'phpvm switch' to add phpvm's bin directory")
+	case pathVersion != resolution.Version:
+		runs := "PHP " + pathVersion
+		if pathVersion == "" {
+			runs = "a PHP not managed by phpvm"
+		}
+		fmt.Printf("⚠️  Warning: 'php' on your PATH is %s, which runs %s\n", pathBinary, runs)
+		fmt.Println("   Load the shell integration ('phpvm init') or run 'phpvm switch --auto' to use the resolved version")
+	}
+	return nil
+}
+
+// showCurrentSystem reports the system PHP when it is selected
+func showCurrentSystem(resolution *Resolution) error {
+	fmt.Println("system")
+	fmt.Printf("  set by:  %s\n", describeSource(resolution))
+	if resolution.Alias != "" {
+		fmt.Printf("  alias:   %s -> %s\n", resolution.Alias, resolution.Request)
+	}
+	binary, err := systemBinary("php")
+	if err != nil {
+		fmt.Printf("⚠️  Warning: %v\n", err)
+		return nil
+	}
+	fmt.Printf("  binary:  %s\n", binary)
+
+	if pathVersion, pathBinary := pathPHPVersion(); pathVersion != "" && pathVersion != systemVersion {
+		fmt.Printf("⚠️  Warning: 'php' on your PATH is %s, which runs PHP %s\n", pathBinary, pathVersion)
+		fmt.Println("   Load the shell integration ('phpvm init') or run 'phpvm switch --auto' to use the resolved version")
+	}
+	return nil
+}
+
+// describeSource turns a resolution's source into a readable origin
+func describeSource(resolution *Resolution) string {
+	root, _ := phpvmHome()
+	switch {
+	case strings.HasPrefix(resolution.Source, sessionVersionEnv):
+		return "shell session (" + sessionVersionEnv + ")"
+	case resolution.Source == filepath.Join(root, "version"):
+		return "global default (" + resolution.Source + ")"
+	}
+	return resolution.Source
+}
+
+// pathPHPVersion finds the php that runs first on PATH and, when it belongs
+// to phpvm, the version it runs. The version is empty for other binaries.
+func pathPHPVersion() (version, path string) {
+	path, err := exec.LookPath("php")
+	if err != nil {
+		return "", ""
+	}
+	root, err := phpvmHome()
+	if err != nil {
+		return "", path
+	}
+
+	if path == filepath.Join(root, "bin", "php") {
+		active, _ := activeVersion()
+		return active, path
+	}
+	// ~/.phpvm/versions/<version>/shims/php or ~/.phpvm/versions/<version>/php
+	rel, err := filepath.Rel(filepath.Join(root, "versions"), path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return "", path
+	}
+	return strings.Split(rel, string(filepath.Separator))[0], path
+}
@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yourusername/phpvm/data"
+)
+
+var eolStrictFlag bool
+
+var eolCmd = &cobra.Command{
+	Use:   "eol [directory]",
+	Short: "Report installed versions and projects on unsupported PHP branches",
+	Long: `Show the support status of every installed PHP version, then look for
+.php-version files under a directory (the current one by default) and report
+the projects that ask for a branch past its end of life. vendor, node_modules
+and .git directories are skipped. With --strict, finding an end-of-life
+version makes the command exit with a non-zero status.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir := "."
+		if len(args) == 1 {
+			dir = args[0]
+		}
+		return eolReport(dir, eolStrictFlag)
+	},
+}
+
+func init() {
+	eolCmd.Flags().BoolVar(&eolStrictFlag, "strict", false, "exit with an error when an end-of-life version is found")
+	RootCmd.AddCommand(eolCmd)
+}
+
+// supportLabel describes a version's support status for tables
+func supportLabel(version string) string {
+	branch := data.FindBranch(version)
+	if branch == nil {
+		return "-"
+	}
+	switch branch.Status(time.Now()) {
+	case data.SupportActive:
+		return "active"
+	case data.SupportSecurity:
+		return "security only"
+	}
+	return "EOL " + branch.SecuritySupportUntil.Format("2006-01-02")
+}
+
+// warnIfEOL prints a warning to stderr when a version's branch no longer
+// gets security fixes
+func warnIfEOL(version string) {
+	branch := data.FindBranch(version)
+	if branch == nil || branch.Status(time.Now()) != data.SupportEOL {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "⚠️  Warning: PHP %s reached end of life on %s and no longer receives security fixes\n",
+		branch.Branch, branch.SecuritySupportUntil.Format("2006-01-02"))
+}
+
+// requestBranch returns the branch a version request resolves to: the
+// branch of an exact or partial version, or the newest branch a constraint
+// allows
+func requestBranch(request string) *data.PHPBranch {
+	if branch := data.FindBranch(request); branch != nil {
+		return branch
+	}
+	constraint := data.VersionRequestConstraint(request)
+	for i := len(data.Branches) - 1; i >= 0; i-- {
+		branch := &data.Branches[i]
+		for _, candidate := range []string{branch.Branch + ".0", branch.Branch + ".99"} {
+			if ok, err := data.SatisfiesConstraint(candidate, constraint); err == nil && ok {
+				return branch
+			}
+		}
+	}
+	return nil
+}
+
+func eolReport(dir string, strict bool) error {
+	unsupported := 0
+
+	versions, err := installedVersions()
+	if err != nil {
+		return err
+	}
+	fmt.Println("Installed versions:")
+	if len(versions) == 0 {
+		fmt.Println("  (none)")
+	}
+	for _, version := range versions {
+		label := supportLabel(version)
+		if data.VersionSupport(version) == data.SupportEOL {
+			unsupported++
+			label = "⚠️  " + label
+		}
+		fmt.Printf("  %-12s %s\n", version, label)
+	}
+
+	fmt.Printf("\nProjects under %s:\n", dir)
+	found := 0
+	err = filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			// Unreadable directories are skipped rather than aborting the scan
+			if entry != nil && entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if entry.IsDir() {
+			switch entry.Name() {
+			case ".git", "vendor", "node_modules":
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if entry.Name() != versionFile {
+			return nil
+		}
+
+		found++
+		request, err := readVersionFile(path)
+		if err != nil {
+			fmt.Printf("  %-40s %v\n", filepath.Dir(path), err)
+			return nil
+		}
+		branch := requestBranch(request)
+		switch {
+		case branch == nil:
+			fmt.Printf("  %-40s %-10s unknown branch\n", filepath.Dir(path), request)
+		case branch.Status(time.Now()) == data.SupportEOL:
+			unsupported++
+			fmt.Printf("  %-40s %-10s ⚠️  PHP %s EOL since %s\n", filepath.Dir(path), request,
+				branch.Branch, branch.SecuritySupportUntil.Format("2006-01-02"))
+		default:
+			fmt.Printf("  %-40s %-10s %s\n", filepath.Dir(path), request, supportLabel(branch.Branch))
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to scan %s: %v", dir, err)
+	}
+	if found == 0 {
+		fmt.Printf("  (no %s files found)\n", versionFile)
+	}
+
+	if unsupported > 0 {
+		fmt.Printf("\n%d end-of-life version(s) found. See https://www.php.net/supported-versions.php\n", unsupported)
+		if strict {
+			return fmt.Errorf("end-of-life PHP versions in use")
+		}
+		return nil
+	}
+	fmt.Println("\n✅ No end-of-life versions found")
+	return nil
+}
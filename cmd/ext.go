@@ -0,0 +1,262 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// zendExtensions lists extensions that must be loaded with zend_extension=
+var zendExtensions = map[string]bool{
+	"xdebug":  true,
+	"opcache": true,
+}
+
+var extVersionFlag string
+
+var extCmd = &cobra.Command{
+	Use:   "ext",
+	Short: "Manage PHP extensions",
+	Long:  `Manage PHP extensions for the PHP versions installed by phpvm.`,
+}
+
+var extInstallCmd = &cobra.Command{
+	Use:   "install <name>[@version]",
+	Short: "Build and enable a PECL extension",
+	Long: `Download an extension from PECL, build it with the target version's
+phpize and php-config, and enable it through that version's conf.d directory.
+Without @version the latest stable release is installed.
+
+The extension is installed for the version given with --version, or for the
+active PHP version.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		version, err := targetVersion(extVersionFlag)
+		if err != nil {
+			return err
+		}
+		return installExtension(args[0], version)
+	},
+}
+
+func init() {
+	extCmd.PersistentFlags().StringVar(&extVersionFlag, "version", "", "PHP version to operate on (defaults to the active version)")
+	extCmd.AddCommand(extInstallCmd)
+	RootCmd.AddCommand(extCmd)
+}
+
+// Extension names and versions end up in file names and URLs, so only
+// the characters PECL uses are accepted
+var (
+	extensionNamePattern    = regexp.MustCompile(`^[a-z0-9_]+$`)
+	extensionVersionPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+)
+
+// parseExtensionSpec splits "redis@6.0.2" into name and version
+func parseExtensionSpec(spec string) (string, string, error) {
+	name, version, hasVersion := strings.Cut(spec, "@")
+	name = strings.ToLower(name)
+	if name == "" {
+		return "", "", fmt.Errorf("extension name is required")
+	}
+	if !extensionNamePattern.MatchString(name) {
+		return "", "", fmt.Errorf("invalid extension name %q", name)
+	}
+	if hasVersion && !extensionVersionPattern.MatchString(version) {
+		return "", "", fmt.Errorf("invalid extension version %q", version)
+	}
+	return name, version, nil
+}
+
+// peclURL returns the PECL download URL for an extension release
+func peclURL(name, version string) string {
+	if version == "" {
+		return fmt.Sprintf("https://pecl.php.net/get/%s", name)
+	}
+	return fmt.Sprintf("https://pecl.php.net/get/%s-%s.tgz", name, version)
+}
+
+// findBuildTool locates phpize/php-config (or another tool, like php-fpm)
+// inside a version's install prefix
+func findBuildTool(installDir, tool string) (string, error) {
+	for _, candidate := range []string{
+		filepath.Join(installDir, "bin", tool),
+		filepath.Join(installDir, "sbin", tool),
+		filepath.Join(installDir, tool),
+	} {
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("%s not found in %s; this PHP build does not ship development tools", tool, installDir)
+}
+
+func installExtension(spec, phpVersion string) error {
+	name, extVersion, err := parseExtensionSpec(spec)
+	if err != nil {
+		return err
+	}
+
+	installDir, err := installedVersionDir(phpVersion)
+	if err != nil {
+		return err
+	}
+
+	soPath, builtVersion, err := buildExtension(name, extVersion, phpVersion, installDir)
+	if err != nil {
+		return err
+	}
+
+	if err := enableExtension(phpVersion, name, soPath); err != nil {
+		return err
+	}
+
+	meta, err := loadMetadata(phpVersion)
+	if err != nil {
+		return err
+	}
+	if meta.Extensions == nil {
+		meta.Extensions = map[string]ExtensionState{}
+	}
+	meta.Extensions[name] = ExtensionState{
+		Version: builtVersion,
+		Path:    soPath,
+		Zend:    zendExtensions[name],
+		Enabled: true,
+	}
+	meta.Modules = nil // the cached module scan is stale now
+	if err := saveMetadata(meta); err != nil {
+		return err
+	}
+
+	fmt.Printf("✅ Extension %s %s installed and enabled for PHP %s\n", name, builtVersion, phpVersion)
+	return nil
+}
+
+// buildExtension downloads a PECL package and compiles it against the given
+// PHP version, returning the path of the installed shared object and the
+// extension version that was built
+func buildExtension(name, extVersion, phpVersion, installDir string) (string, string, error) {
+	phpize, err := findBuildTool(installDir, "phpize")
+	if err != nil {
+		return "", "", err
+	}
+	phpConfig, err := findBuildTool(installDir, "php-config")
+	if err != nil {
+		return "", "", err
+	}
+
+	workDir, err := os.MkdirTemp("", "phpvm-ext-")
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create build directory: %v", err)
+	}
+	defer os.RemoveAll(workDir)
+
+	url := peclURL(name, extVersion)
+	tarball := filepath.Join(workDir, name+".tgz")
+	fmt.Printf("Downloading %s from %s...\n", name, url)
+	// Without a version the URL always serves the latest release
+	download := cachedDownload
+	if extVersion == "" {
+		download = downloadFile
+	}
+	if err := download(url, tarball); err != nil {
+		return "", "", fmt.Errorf("failed to download extension %s: %v", name, err)
+	}
+
+	if err := extractTarGz(tarball, workDir); err != nil {
+		return "", "", fmt.Errorf("failed to extract extension %s: %v", name, err)
+	}
+
+	srcDir, err := findExtensionSource(workDir, name)
+	if err != nil {
+		return "", "", err
+	}
+	if extVersion == "" {
+		// PECL tarballs unpack into <name>-<version>
+		base := filepath.Base(srcDir)
+		if i := strings.LastIndex(base, "-"); i >= 0 {
+			extVersion = base[i+1:]
+		}
+	}
+
+	fmt.Printf("Building %s %s for PHP %s...\n", name, extVersion, phpVersion)
+	steps := [][]string{
+		{phpize},
+		{"./configure", "--with-php-config=" + phpConfig},
+		{"make", fmt.Sprintf("-j%d", runtime.NumCPU())},
+	}
+	for _, step := range steps {
+		if err := runBuildStep(srcDir, step[0], step[1:]...); err != nil {
+			return "", "", err
+		}
+	}
+
+	built, err := filepath.Glob(filepath.Join(srcDir, "modules", "*.so"))
+	if err != nil || len(built) == 0 {
+		return "", "", fmt.Errorf("build of %s produced no shared object", name)
+	}
+
+	extDir := filepath.Join(installDir, "ext")
+	if err := os.MkdirAll(extDir, 0755); err != nil {
+		return "", "", fmt.Errorf("failed to create extension directory: %v", err)
+	}
+
+	soPath := filepath.Join(extDir, filepath.Base(built[0]))
+	if err := copyFile(built[0], soPath, 0755); err != nil {
+		return "", "", fmt.Errorf("failed to install %s: %v", filepath.Base(soPath), err)
+	}
+
+	return soPath, extVersion, nil
+}
+
+// findExtensionSource returns the unpacked source directory of a PECL package
+func findExtensionSource(workDir, name string) (string, error) {
+	matches, _ := filepath.Glob(filepath.Join(workDir, "*", "config.m4"))
+	if len(matches) == 0 {
+		return "", fmt.Errorf("no config.m4 found in the %s package", name)
+	}
+	return filepath.Dir(matches[0]), nil
+}
+
+// runBuildStep runs one build command, showing its output only on failure
+func runBuildStep(dir, name string, args ...string) error {
+	cmd := exec.Command(name, args...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s failed: %v\n%s", filepath.Base(name), err, output)
+	}
+	return nil
+}
+
+// enableExtension writes a conf.d ini file that loads the extension
+func enableExtension(phpVersion, name, soPath string) error {
+	installDir, err := versionDir(phpVersion)
+	if err != nil {
+		return err
+	}
+
+	confDir := filepath.Join(installDir, "conf.d")
+	if err := os.MkdirAll(confDir, 0755); err != nil {
+		return fmt.Errorf("failed to create conf.d directory: %v", err)
+	}
+
+	directive := "extension"
+	if zendExtensions[name] {
+		directive = "zend_extension"
+	}
+
+	iniPath := filepath.Join(confDir, fmt.Sprintf("20-%s.ini", name))
+	content := fmt.Sprintf("; Generated by phpvm\n%s=%s\n", directive, soPath)
+	if err := os.WriteFile(iniPath, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to enable extension %s: %v", name, err)
+	}
+	return nil
+}
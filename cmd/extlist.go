@@ -0,0 +1,226 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var extListRefreshFlag bool
+
+var extListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Show extensions across installed PHP versions",
+	Long: `Show a matrix of the extensions available in each installed PHP version.
+Each binary is run with -m and -i; the results are cached in the version's
+metadata until --refresh is passed or an extension is installed or toggled.
+
+  ● loaded    ○ shared object available but not loaded    - missing`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		versions := []string{}
+		if extVersionFlag != "" {
+			versions = append(versions, extVersionFlag)
+		} else {
+			installed, err := installedVersions()
+			if err != nil {
+				return err
+			}
+			versions = installed
+		}
+		return listExtensions(versions, extListRefreshFlag)
+	},
+}
+
+func init() {
+	extListCmd.Flags().BoolVar(&extListRefreshFlag, "refresh", false, "re-run the binaries instead of using cached results")
+	extCmd.AddCommand(extListCmd)
+}
+
+func listExtensions(versions []string, refresh bool) error {
+	if len(versions) == 0 {
+		fmt.Println("No PHP versions installed. Use 'phpvm install <version>' first")
+		return nil
+	}
+
+	scans := map[string]*ModuleScan{}
+	names := map[string]bool{}
+	for _, version := range versions {
+		scan, err := moduleScan(version, refresh)
+		if err != nil {
+			fmt.Printf("⚠️  Warning: Could not inspect PHP %s: %v\n", version, err)
+			continue
+		}
+		scans[version] = scan
+		for _, name := range scan.Loaded {
+			names[name] = true
+		}
+		for _, name := range scan.Loadable {
+			names[name] = true
+		}
+	}
+
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	fmt.Printf("%-20s", "Extension")
+	for _, version := range versions {
+		fmt.Printf(" %-10s", version)
+	}
+	fmt.Println()
+	fmt.Println(strings.Repeat("-", 20+11*len(versions)))
+
+	for _, name := range sorted {
+		fmt.Printf("%-20s", name)
+		for _, version := range versions {
+			mark := "-"
+			if scan := scans[version]; scan != nil {
+				if slices.Contains(scan.Loaded, name) {
+					mark = "●"
+				} else if slices.Contains(scan.Loadable, name) {
+					mark = "○"
+				}
+			}
+			fmt.Printf(" %-10s", mark)
+		}
+		fmt.Println()
+	}
+
+	fmt.Println("\n● = loaded, ○ = available but not loaded, - = missing")
+	return nil
+}
+
+// forgetModuleScan drops a version's cached extension scan after its
+// configuration changed
+func forgetModuleScan(version string) error {
+	meta, err := loadMetadata(version)
+	if err != nil {
+		return err
+	}
+	if meta.Modules == nil {
+		return nil
+	}
+	meta.Modules = nil
+	return saveMetadata(meta)
+}
+
+// moduleScan returns the cached extension scan of a version, running the
+// binary when there is no cache or refresh is requested
+func moduleScan(version string, refresh bool) (*ModuleScan, error) {
+	meta, err := loadMetadata(version)
+	if err != nil {
+		return nil, err
+	}
+	if meta.Modules != nil && !refresh {
+		return meta.Modules, nil
+	}
+
+	scan, err := scanModules(version)
+	if err != nil {
+		return nil, err
+	}
+
+	meta.Modules = scan
+	if err := saveMetadata(meta); err != nil {
+		return nil, err
+	}
+	return scan, nil
+}
+
+// scanModules runs a version's binary with -m and -i to find which
+// extensions are loaded and which shared objects could be loaded
+func scanModules(version string) (*ModuleScan, error) {
+	cmd, err := phpCommand(version, "-m")
+	if err != nil {
+		return nil, err
+	}
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("php -m failed: %v", err)
+	}
+	loaded := parseModuleList(string(output))
+
+	cmd, err = phpCommand(version, "-i")
+	if err != nil {
+		return nil, err
+	}
+	output, err = cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("php -i failed: %v", err)
+	}
+
+	installDir, err := installedVersionDir(version)
+	if err != nil {
+		return nil, err
+	}
+	extensionDirs := []string{filepath.Join(installDir, "ext")}
+	if dir := parsePHPInfoValue(string(output), "extension_dir"); dir != "" {
+		extensionDirs = append(extensionDirs, dir)
+	}
+
+	var loadable []string
+	for _, dir := range extensionDirs {
+		objects, _ := filepath.Glob(filepath.Join(dir, "*.so"))
+		for _, object := range objects {
+			name := normalizeModuleName(strings.TrimSuffix(filepath.Base(object), ".so"))
+			if !slices.Contains(loaded, name) && !slices.Contains(loadable, name) {
+				loadable = append(loadable, name)
+			}
+		}
+	}
+	sort.Strings(loadable)
+
+	return &ModuleScan{Loaded: loaded, Loadable: loadable, ScannedAt: time.Now()}, nil
+}
+
+// parseModuleList parses the output of php -m into normalized module names
+func parseModuleList(output string) []string {
+	var modules []string
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "[") {
+			continue
+		}
+		name := normalizeModuleName(line)
+		if !slices.Contains(modules, name) {
+			modules = append(modules, name)
+		}
+	}
+	sort.Strings(modules)
+	return modules
+}
+
+// parsePHPInfoValue extracts the local value of a directive from php -i
+// output, where lines look like "extension_dir => /path => /path"
+func parsePHPInfoValue(output, directive string) string {
+	for _, line := range strings.Split(output, "\n") {
+		parts := strings.Split(line, " => ")
+		if len(parts) >= 2 && strings.TrimSpace(parts[0]) == directive {
+			value := strings.TrimSpace(parts[1])
+			if value == "no value" {
+				return ""
+			}
+			return value
+		}
+	}
+	return ""
+}
+
+// normalizeModuleName maps names reported by php -m and shared object file
+// names onto the lowercase extension names used by composer.json
+func normalizeModuleName(name string) string {
+	name = strings.ToLower(strings.TrimPrefix(name, "php_"))
+	switch name {
+	case "zend opcache":
+		return "opcache"
+	}
+	return strings.ReplaceAll(name, " ", "_")
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+)
+
+// autoVersionEnv records the version the directory hook put on PATH
+const autoVersionEnv = "PHPVM_AUTO_VERSION"
+
+var hookCmd = &cobra.Command{
+	Use:   "hook <bash|zsh|fish>",
+	Short: "Print shell code that follows the current directory's PHP version",
+	Long: `Resolve the PHP version for the current directory from .php-version or
+composer.json and print shell code that puts it first on PATH. Nothing is
+printed when the version hasn't changed since the last run, or while a
+'phpvm shell' session version is set. The shell integration runs this after
+every directory change; it never touches the network.`,
+	Hidden:       true,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runDirectoryHook(args[0])
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(hookCmd)
+}
+
+func runDirectoryHook(shell string) error {
+	if os.Getenv(sessionVersionEnv) != "" {
+		return nil
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil
+	}
+	want := ""
+	resolution, err := resolveProjectVersion(cwd)
+	if err != nil {
+		// A broken project file shouldn't break the prompt
+		fmt.Fprintf(os.Stderr, "⚠️  phpvm: %v\n", err)
+	} else if resolution != nil {
+		want = resolution.Version
+		if want == "" {
+			hint := "phpvm install <version>"
+			if candidate, err := bestCatalogMatch(resolution.Request); err == nil && candidate != "" {
+				hint = "phpvm install " + candidate
+			}
+			fmt.Fprintf(os.Stderr, "ℹ️  %s asks for PHP %s, which isn't installed. Run '%s'\n",
+				resolution.Source, resolution.Request, hint)
+		}
+	}
+
+	if want == os.Getenv(autoVersionEnv) {
+		return nil
+	}
+
+	shimDir := ""
+	if want != "" {
+		if shimDir, err = versionShimDir(want); err != nil {
+			return err
+		}
+		if _, err := os.Stat(filepath.Join(shimDir, "php")); err != nil {
+			if shimDir, err = ensureVersionShims(want); err != nil {
+				return err
+			}
+		}
+	}
+	path, err := sessionPATH(want, shimDir)
+	if err != nil {
+		return err
+	}
+	return emitEnv(os.Stdout, shell, []string{autoVersionEnv, "PATH"},
+		map[string]string{autoVersionEnv: want, "PATH": path})
+}
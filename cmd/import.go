@@ -0,0 +1,212 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yourusername/phpvm/data"
+)
+
+var (
+	importScanFlag bool
+	importAsFlag   string
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import [path]",
+	Short: "Register an existing PHP binary with phpvm",
+	Long: `Register a PHP binary installed outside phpvm, such as /usr/bin/php8.1 from
+the ondrej PPA or /opt/remi/php82/root/usr/bin/php, under its version. The
+version is detected by running the binary. Imported versions work with switch,
+list, ini and Composer like installed ones, but phpvm never deletes or
+upgrades the binary itself.
+
+With --scan, standard locations (/usr/bin, /usr/local/bin, /opt/remi and
+Homebrew prefixes) are searched and every PHP found is imported.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if importScanFlag {
+			if len(args) > 0 || importAsFlag != "" {
+				return fmt.Errorf("--scan can't be combined with a path or --as")
+			}
+			return scanAndImport()
+		}
+		if len(args) != 1 {
+			return fmt.Errorf("a PHP binary path is required (or --scan)")
+		}
+		_, err := importPHP(args[0], importAsFlag)
+		return err
+	},
+}
+
+func init() {
+	importCmd.Flags().BoolVar(&importScanFlag, "scan", false, "search standard locations for PHP binaries")
+	importCmd.Flags().StringVar(&importAsFlag, "as", "", "register under this name instead of the detected version")
+	RootCmd.AddCommand(importCmd)
+}
+
+// importScanPatterns are the places system and package manager PHP binaries
+// are usually found
+var importScanPatterns = []string{
+	"/usr/bin/php*",
+	"/usr/local/bin/php*",
+	"/opt/remi/php*/root/usr/bin/php",
+	"/opt/homebrew/opt/php*/bin/php",
+	"/usr/local/opt/php*/bin/php",
+	"/home/linuxbrew/.linuxbrew/opt/php*/bin/php",
+}
+
+// phpBinaryName matches php, php8.1 or php82 but not php-config, phpize or
+// php-fpm
+var phpBinaryName = regexp.MustCompile(`^php[0-9.]*$`)
+
+// phpProbe is what a PHP binary reports about itself
+type phpProbe struct {
+	Version string
+	INI     string
+	ScanDir string
+}
+
+// probePHP runs a binary to find its version and the ini files it loads
+// when phpvm isn't involved
+func probePHP(binary string) (*phpProbe, error) {
+	cmd := exec.Command(binary, "-r", `echo PHP_VERSION, "\n", (string) php_ini_loaded_file(), "\n", PHP_CONFIG_FILE_SCAN_DIR, "\n";`)
+	var env []string
+	for _, entry := range os.Environ() {
+		if !strings.HasPrefix(entry, "PHPRC=") && !strings.HasPrefix(entry, "PHP_INI_SCAN_DIR=") {
+			env = append(env, entry)
+		}
+	}
+	cmd.Env = env
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to run %s: %v", binary, err)
+	}
+	lines := strings.Split(strings.TrimRight(string(output), "\n"), "\n")
+	for len(lines) < 3 {
+		lines = append(lines, "")
+	}
+	if _, err := data.ParseVersion(lines[0]); err != nil {
+		return nil, fmt.Errorf("%s doesn't look like PHP (reported version %q)", binary, lines[0])
+	}
+	return &phpProbe{Version: lines[0], INI: lines[1], ScanDir: lines[2]}, nil
+}
+
+// importPHP registers an external PHP binary under its version (or label),
+// returning the name it was registered as
+func importPHP(path, label string) (string, error) {
+	binary, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("invalid path %s: %v", path, err)
+	}
+	if info, err := os.Stat(binary); err != nil || info.IsDir() {
+		return "", fmt.Errorf("%s is not a PHP binary", path)
+	}
+	// Register the binary itself rather than a symlink such as an
+	// alternatives link, which can later point at another PHP
+	if binary, err = filepath.EvalSymlinks(binary); err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %v", path, err)
+	}
+
+	probe, err := probePHP(binary)
+	if err != nil {
+		return "", err
+	}
+	if label == "" {
+		// Distribution builds report versions like 8.1.2-1ubuntu2.14
+		parsed, _ := data.ParseVersion(probe.Version)
+		label = parsed.String()
+	}
+	if label != filepath.Base(label) || strings.HasPrefix(label, ".") {
+		return "", fmt.Errorf("invalid version name %q", label)
+	}
+
+	if isVersionInstalled(label) {
+		meta, err := loadMetadata(label)
+		if err != nil {
+			return "", err
+		}
+		if meta.Imported != nil && meta.Imported.Binary == binary {
+			fmt.Printf("ℹ️  %s is already imported as PHP %s\n", binary, label)
+			return label, nil
+		}
+		return "", fmt.Errorf("PHP %s is already installed. Use --as to import %s under another name", label, binary)
+	}
+
+	dir, err := versionDir(label)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "conf.d"), 0755); err != nil {
+		return "", fmt.Errorf("failed to create %s: %v", dir, err)
+	}
+	if err := os.Symlink(binary, filepath.Join(dir, "php")); err != nil {
+		os.RemoveAll(dir)
+		return "", fmt.Errorf("failed to link %s: %v", binary, err)
+	}
+
+	// Start from the binary's own php.ini so it behaves as before
+	if probe.INI != "" {
+		if err := copyFile(probe.INI, filepath.Join(dir, "php.ini"), 0644); err != nil {
+			fmt.Printf("⚠️  Warning: Failed to copy %s: %v\n", probe.INI, err)
+		}
+	}
+	if err := ensureINI(label, defaultINIPreset); err != nil {
+		fmt.Printf("⚠️  Warning: Failed to create php.ini: %v\n", err)
+	}
+
+	meta, err := loadMetadata(label)
+	if err != nil {
+		return "", err
+	}
+	meta.Imported = &ImportedInstall{Binary: binary, INI: probe.INI, ScanDir: probe.ScanDir}
+	if err := saveMetadata(meta); err != nil {
+		return "", err
+	}
+
+	fmt.Printf("✅ Imported %s as PHP %s\n", binary, label)
+	warnIfEOL(label)
+
+	if err := installComposer(label, dir); err != nil {
+		fmt.Printf("⚠️  Warning: Failed to install Composer: %v\n", err)
+	}
+	return label, nil
+}
+
+// scanAndImport imports every PHP binary found in the standard locations,
+// skipping symlinks to binaries that were already seen
+func scanAndImport() error {
+	seen := map[string]bool{}
+	found := 0
+	for _, pattern := range importScanPatterns {
+		matches, _ := filepath.Glob(pattern)
+		for _, match := range matches {
+			if !phpBinaryName.MatchString(filepath.Base(match)) {
+				continue
+			}
+			real, err := filepath.EvalSymlinks(match)
+			if err != nil || seen[real] {
+				continue
+			}
+			if info, err := os.Stat(real); err != nil || info.IsDir() || info.Mode()&0111 == 0 {
+				continue
+			}
+			seen[real] = true
+			found++
+
+			if _, err := importPHP(real, ""); err != nil {
+				fmt.Printf("⚠️  Warning: Skipped %s: %v\n", match, err)
+			}
+		}
+	}
+	if found == 0 {
+		fmt.Println("No PHP binaries found in the standard locations")
+	}
+	return nil
+}
@@ -0,0 +1,281 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yourusername/phpvm/data"
+)
+
+// defaultINIPreset is the php.ini template used when none is requested
+const defaultINIPreset = "development"
+
+var (
+	iniVersionFlag string
+	iniPresetFlag  string
+	iniForceFlag   bool
+)
+
+var iniCmd = &cobra.Command{
+	Use:   "ini",
+	Short: "Manage the php.ini of a PHP version",
+	Long: `Manage the php.ini and conf.d directory phpvm keeps for each installed
+PHP version. The php wrapper loads them through PHPRC and PHP_INI_SCAN_DIR.`,
+}
+
+var iniInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Create php.ini from a preset",
+	Long: `Create the version's php.ini from the development or production preset.
+An existing php.ini is only replaced with --force.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		version, err := targetVersion(iniVersionFlag)
+		if err != nil {
+			return err
+		}
+		return initINI(version, iniPresetFlag, iniForceFlag)
+	},
+}
+
+var iniGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Show the value of an ini setting",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		version, err := targetVersion(iniVersionFlag)
+		if err != nil {
+			return err
+		}
+		value, source, err := getINIValue(version, args[0])
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s = %s\t(%s)\n", args[0], value, source)
+		return nil
+	},
+}
+
+var iniSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Change an ini setting",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		version, err := targetVersion(iniVersionFlag)
+		if err != nil {
+			return err
+		}
+		if err := setINIValue(version, args[0], args[1]); err != nil {
+			return err
+		}
+		fmt.Printf("✅ %s = %s for PHP %s\n", args[0], args[1], version)
+		return nil
+	},
+}
+
+var iniEditCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Open php.ini in $EDITOR",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		version, err := targetVersion(iniVersionFlag)
+		if err != nil {
+			return err
+		}
+		return editINI(version)
+	},
+}
+
+func init() {
+	iniCmd.PersistentFlags().StringVar(&iniVersionFlag, "version", "", "PHP version to operate on (defaults to the active version)")
+	iniInitCmd.Flags().StringVar(&iniPresetFlag, "preset", defaultINIPreset, "php.ini template to use (development or production)")
+	iniInitCmd.Flags().BoolVar(&iniForceFlag, "force", false, "overwrite an existing php.ini")
+
+	iniCmd.AddCommand(iniInitCmd, iniGetCmd, iniSetCmd, iniEditCmd)
+	RootCmd.AddCommand(iniCmd)
+}
+
+// iniPath returns the php.ini path for an installed PHP version
+func iniPath(version string) (string, error) {
+	installDir, err := installedVersionDir(version)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(installDir, "php.ini"), nil
+}
+
+// ensureINI creates php.ini and conf.d for a version if they don't exist yet
+func ensureINI(version, preset string) error {
+	path, err := iniPath(version)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Join(filepath.Dir(path), "conf.d"), 0755); err != nil {
+		return fmt.Errorf("failed to create conf.d directory: %v", err)
+	}
+
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+	return writeINIPreset(path, version, preset)
+}
+
+func initINI(version, preset string, force bool) error {
+	path, err := iniPath(version)
+	if err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(path); err == nil && !force {
+		return fmt.Errorf("%s already exists. Use --force to replace it", path)
+	}
+
+	if err := os.MkdirAll(filepath.Join(filepath.Dir(path), "conf.d"), 0755); err != nil {
+		return fmt.Errorf("failed to create conf.d directory: %v", err)
+	}
+	if err := writeINIPreset(path, version, preset); err != nil {
+		return err
+	}
+	if err := forgetModuleScan(version); err != nil {
+		return err
+	}
+
+	fmt.Printf("✅ Created %s from the %s preset\n", path, preset)
+	return nil
+}
+
+// writeINIPreset renders a preset into a php.ini file
+func writeINIPreset(path, version, presetName string) error {
+	preset, ok := data.GetINIPreset(presetName)
+	if !ok {
+		return fmt.Errorf("unknown php.ini preset %q (expected development or production)", presetName)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "; php.ini for PHP %s, generated by phpvm (%s preset)\n", version, preset.Name)
+	b.WriteString("; Use 'phpvm ini set <key> <value>' or 'phpvm ini edit' to change it.\n\n")
+	for _, setting := range preset.Settings {
+		fmt.Fprintf(&b, "%s = %s\n", setting[0], setting[1])
+	}
+
+	if err := os.WriteFile(path, []byte(b.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write php.ini: %v", err)
+	}
+	return nil
+}
+
+// parseINILine returns the key and value of an active "key = value" line
+func parseINILine(line string) (string, string, bool) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, ";") || strings.HasPrefix(line, "[") {
+		return "", "", false
+	}
+	key, value, found := strings.Cut(line, "=")
+	if !found {
+		return "", "", false
+	}
+	return strings.TrimSpace(key), strings.Trim(strings.TrimSpace(value), `"`), true
+}
+
+// getINIValue returns the effective value of a key across php.ini and conf.d,
+// along with the file that set it. Later conf.d files win, like in PHP.
+func getINIValue(version, key string) (string, string, error) {
+	path, err := iniPath(version)
+	if err != nil {
+		return "", "", err
+	}
+
+	files := []string{path}
+	scanned, _ := filepath.Glob(filepath.Join(filepath.Dir(path), "conf.d", "*.ini"))
+	sort.Strings(scanned)
+	files = append(files, scanned...)
+
+	var value, source string
+	for _, file := range files {
+		f, err := os.Open(file)
+		if err != nil {
+			continue
+		}
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			if k, v, ok := parseINILine(scanner.Text()); ok && k == key {
+				value, source = v, file
+			}
+		}
+		f.Close()
+	}
+
+	if source == "" {
+		return "", "", fmt.Errorf("%s is not set for PHP %s", key, version)
+	}
+	return value, source, nil
+}
+
+// setINIValue updates or appends a setting in the version's php.ini
+func setINIValue(version, key, value string) error {
+	if err := ensureINI(version, defaultINIPreset); err != nil {
+		return err
+	}
+	path, err := iniPath(version)
+	if err != nil {
+		return err
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read php.ini: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimRight(string(content), "\n"), "\n")
+	replaced := false
+	for i, line := range lines {
+		if k, _, ok := parseINILine(line); ok && k == key {
+			lines[i] = fmt.Sprintf("%s = %s", key, value)
+			replaced = true
+		}
+	}
+	if !replaced {
+		lines = append(lines, fmt.Sprintf("%s = %s", key, value))
+	}
+
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		return fmt.Errorf("failed to write php.ini: %v", err)
+	}
+	// Settings such as extension= change which modules load
+	return forgetModuleScan(version)
+}
+
+// editINI opens the version's php.ini in the user's editor
+func editINI(version string) error {
+	if err := ensureINI(version, defaultINIPreset); err != nil {
+		return err
+	}
+	path, err := iniPath(version)
+	if err != nil {
+		return err
+	}
+
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	cmd := exec.Command("sh", "-c", editor+` "$1"`, "sh", path)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return err
+	}
+	return forgetModuleScan(version)
+}
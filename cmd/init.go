@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var initCmd = &cobra.Command{
+	Use:   "init <bash|zsh|fish>",
+	Short: "Print the shell integration",
+	Long: `Print the shell integration for bash, zsh or fish. It wraps the phpvm
+command so that 'phpvm shell' can change the current shell session, and
+installs a hook that switches PHP whenever you change into a directory with
+a .php-version file or composer.json (use --no-auto to leave it out). With
+composer.isolated-home enabled it also keeps the Composer vendor/bin of the
+version in use on PATH.
+
+Add it to your shell's startup file:
+
+  bash:  echo 'eval "$(phpvm init bash)"' >> ~/.bashrc
+  zsh:   echo 'eval "$(phpvm init zsh)"' >> ~/.zshrc
+  fish:  echo 'phpvm init fish | source' >> ~/.config/fish/config.fish`,
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: []string{"bash", "zsh", "fish"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		script, err := shellIntegration(args[0], !initNoAutoFlag)
+		if err != nil {
+			return err
+		}
+		fmt.Print(script)
+		return nil
+	},
+}
+
+var initNoAutoFlag bool
+
+func init() {
+	initCmd.Flags().BoolVar(&initNoAutoFlag, "no-auto", false, "don't switch versions automatically on directory changes")
+	RootCmd.AddCommand(initCmd)
+}
+
+const posixIntegration = `# phpvm shell integration
+phpvm() {
+  if [ "$1" = "shell" ]; then
+    shift
+    eval "$(command phpvm shell --emit %[1]s "$@")"
+    __phpvm_hook
+  else
+    command phpvm "$@"
+  fi
+}
+`
+
+const fishIntegration = `# phpvm shell integration
+function phpvm
+  if test "$argv[1]" = shell
+    command phpvm shell --emit fish $argv[2..-1] | source
+    __phpvm_hook
+  else
+    command phpvm $argv
+  end
+end
+`
+
+// Global Composer vendor/bin PATH entries, for isolated Composer homes
+const (
+	posixVendorBin = `case ":$PATH:" in
+  *:%[1]s:*) ;;
+  *) PATH=%[1]s:$PATH ;;
+esac
+`
+	fishVendorBin = `contains -- %[1]s $PATH; or set -gx PATH %[1]s $PATH
+`
+)
+
+// Directory hooks. They also run once when the integration is loaded, so a
+// new terminal opened inside a project picks up its version.
+const (
+	noHook = `__phpvm_hook() { :; }
+`
+	bashHook = `__phpvm_hook() {
+  eval "$(command phpvm hook bash)"
+}
+__phpvm_prompt() {
+  if [ "$PWD" != "${__phpvm_pwd-}" ]; then
+    __phpvm_pwd=$PWD
+    __phpvm_hook
+  fi
+}
+case ";${PROMPT_COMMAND-};" in
+  *";__phpvm_prompt;"*) ;;
+  *) PROMPT_COMMAND="__phpvm_prompt${PROMPT_COMMAND:+;$PROMPT_COMMAND}" ;;
+esac
+`
+	zshHook = `__phpvm_hook() {
+  eval "$(command phpvm hook zsh)"
+}
+autoload -Uz add-zsh-hook
+add-zsh-hook chpwd __phpvm_hook
+__phpvm_hook
+`
+	fishNoHook = `function __phpvm_hook; end
+`
+	fishHook = `function __phpvm_hook --on-variable PWD
+  command phpvm hook fish | source
+end
+__phpvm_hook
+`
+)
+
+// shellIntegration returns the integration script for a shell, with or
+// without the directory hook. With isolated Composer homes it also puts the
+// global default's Composer vendor/bin on PATH.
+func shellIntegration(shell string, auto bool) (string, error) {
+	vendorBin, err := composerVendorBin("")
+	if err != nil {
+		return "", err
+	}
+
+	switch shell {
+	case "bash", "zsh":
+		script := fmt.Sprintf(posixIntegration, shell)
+		if vendorBin != "" {
+			script += fmt.Sprintf(posixVendorBin, shellQuote(vendorBin))
+		}
+		switch {
+		case !auto:
+			return script + noHook, nil
+		case shell == "bash":
+			return script + bashHook, nil
+		}
+		return script + zshHook, nil
+	case "fish":
+		script := fishIntegration
+		if vendorBin != "" {
+			script += fmt.Sprintf(fishVendorBin, fishQuote(vendorBin))
+		}
+		if !auto {
+			return script + fishNoHook, nil
+		}
+		return script + fishHook, nil
+	}
+	return "", fmt.Errorf("unsupported shell %q (expected bash, zsh or fish)", shell)
+}
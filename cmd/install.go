@@ -7,24 +7,86 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/yourusername/phpvm/data"
 )
 
 var installCmd = &cobra.Command{
-	Use:   "install [version]",
-	Short: "Install a specific PHP version",
+	Use:   "install [version...]",
+	Short: "Install one or more PHP versions",
 	Long: `Download and install a specific version of PHP.
 This will download the source code for the specified version, compile it,
-and install it to the PHPVM directory.`,
-	Args: cobra.ExactArgs(1),
+and install it to the PHPVM directory.
+
+With --from, PHP is installed from a local php binary, a tarball of an
+install prefix (containing bin/php) or an http(s) URL of either, for
+machines that can't reach the catalog downloads. The version is detected by
+running the binary; pass a version to install it under that name instead.
+--sha256 makes the install fail unless the file has that checksum.
+
+A partial version such as 8.3 installs the newest release of that branch.
+Several versions, given as arguments or listed in a file with --file, are
+installed in parallel (--jobs at a time) with a summary at the end; the
+command fails if any of them failed.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		switch {
+		case installFromFlag != "":
+			return cobra.MaximumNArgs(1)(cmd, args)
+		case installFileFlag != "":
+			return nil
+		}
+		return cobra.MinimumNArgs(1)(cmd, args)
+	},
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return installPHP(args[0])
+		cmd.SilenceUsage = true
+		if installFromFlag != "" {
+			if installFileFlag != "" {
+				return fmt.Errorf("--from can't be combined with --file")
+			}
+			label := ""
+			if len(args) == 1 {
+				label = args[0]
+			}
+			return installPHPFrom(installFromFlag, label, installSHA256Flag)
+		}
+		if installSHA256Flag != "" {
+			return fmt.Errorf("--sha256 can only be used with --from")
+		}
+
+		versions := args
+		if installFileFlag != "" {
+			listed, err := readVersionList(installFileFlag)
+			if err != nil {
+				return err
+			}
+			versions = append(versions, listed...)
+		}
+		switch len(versions) {
+		case 0:
+			return fmt.Errorf("%s lists no versions", installFileFlag)
+		case 1:
+			return installPHP(catalogVersion(versions[0]))
+		}
+		return installMany(versions, installJobsFlag)
 	},
 }
 
+var (
+	installINIPreset  string
+	installFromFlag   string
+	installSHA256Flag string
+	installFileFlag   string
+	installJobsFlag   int
+)
+
 func init() {
+	installCmd.Flags().StringVar(&installINIPreset, "ini-preset", defaultINIPreset, "php.ini template to create for the version (development or production)")
+	installCmd.Flags().StringVar(&installFromFlag, "from", "", "install from a local php binary, prefix tarball or URL instead of the catalog")
+	installCmd.Flags().StringVar(&installSHA256Flag, "sha256", "", "expected SHA-256 of the --from file")
+	installCmd.Flags().StringVar(&installFileFlag, "file", "", "install the versions listed in a file, one per line")
+	installCmd.Flags().IntVar(&installJobsFlag, "jobs", 4, "number of versions to install at once")
 	RootCmd.AddCommand(installCmd)
 }
 
@@ -32,27 +94,29 @@ func installPHP(version string) error {
 	fmt.Printf("Preparing to install PHP %s...\n", version)
 
 	// Find the version in our data
-	var phpVersion *data.PHPVersion
-	for _, v := range data.AvailableVersions {
-		if v.Version == version {
-			phpVersion = &v
-			break
-		}
-	}
-
+	phpVersion := data.FindPHPVersion(version)
 	if phpVersion == nil {
-		return fmt.Errorf("PHP version %s not found. Use 'phpvm list' to see available versions", version)
+		return fmt.Errorf("PHP version %s not found. Use 'phpvm list --pre' to see available versions", version)
 	}
 
-	fmt.Printf("Found PHP %s (released: %s)\n", phpVersion.Version, phpVersion.Released.Format("2006-01-02"))
+	switch {
+	case phpVersion.Version == data.NightlyVersion:
+		fmt.Printf("Found PHP nightly (php-src master)\n")
+	case phpVersion.IsPreRelease():
+		fmt.Printf("Found PHP %s pre-release (released: %s)\n", phpVersion.Version, phpVersion.Released.Format("2006-01-02"))
+	default:
+		fmt.Printf("Found PHP %s (released: %s)\n", phpVersion.Version, phpVersion.Released.Format("2006-01-02"))
+	}
+	warnIfEOL(phpVersion.Version)
 
 	// Determine architecture and binary URL
 	var binaryURL string
 	arch := runtime.GOARCH
-	switch arch {
-	case "amd64":
+	switch {
+	case phpVersion.IsSourceBuild():
+	case arch == "amd64":
 		binaryURL = phpVersion.BinaryURLx64
-	case "arm64":
+	case arch == "arm64":
 		binaryURL = phpVersion.BinaryURLarm64
 	default:
 		return fmt.Errorf("unsupported architecture: %s. Only amd64 and arm64 are supported", arch)
@@ -67,8 +131,9 @@ func installPHP(version string) error {
 	installDir := filepath.Join(homeDir, ".phpvm", "versions", version)
 	phpBinary := filepath.Join(installDir, "php")
 	
-	// Check if PHP version is already installed
-	if _, err := os.Stat(phpBinary); err == nil {
+	// Check if PHP version is already installed. Nightly is rebuilt from
+	// the current php-src master every time it is installed.
+	if _, err := os.Stat(phpBinary); err == nil && version != data.NightlyVersion {
 		fmt.Printf("✅ PHP %s is already installed at %s\n", version, installDir)
 		fmt.Printf("Skipping download. Use 'phpvm switch %s' to use this version\n", version)
 		
@@ -93,22 +158,43 @@ func installPHP(version string) error {
 		return fmt.Errorf("failed to create installation directory: %v", err)
 	}
 
-	// Download and install PHP binary
-	fmt.Printf("Downloading PHP binary from %s...\n", binaryURL)
+	if phpVersion.IsSourceBuild() {
+		if err := buildPHP(version, phpVersion.SourceURL, installDir); err != nil {
+			return fmt.Errorf("failed to build PHP %s: %v", version, err)
+		}
+	} else {
+		// Download and install PHP binary
+		fmt.Printf("Downloading PHP binary from %s...\n", binaryURL)
 
-	if err := downloadFile(binaryURL, phpBinary); err != nil {
-		return fmt.Errorf("failed to download PHP binary: %v", err)
-	}
+		// Binary URLs point at the newest build of a branch, so the cache
+		// is keyed by the exact version
+		key := fmt.Sprintf("php-%s-linux-%s", version, arch)
+		if err := cachedDownloadAs(binaryURL, key, phpBinary); err != nil {
+			return fmt.Errorf("failed to download PHP binary: %v", err)
+		}
 
-	// Make binary executable
-	if err := os.Chmod(phpBinary, 0755); err != nil {
-		return fmt.Errorf("failed to make PHP binary executable: %v", err)
+		// Make binary executable
+		if err := os.Chmod(phpBinary, 0755); err != nil {
+			return fmt.Errorf("failed to make PHP binary executable: %v", err)
+		}
 	}
 
 	fmt.Printf("✅ PHP %s installed successfully to %s\n", version, installDir)
+	finishInstall(version, installDir)
+	return nil
+}
+
+// finishInstall sets up a freshly installed version: its php.ini and
+// conf.d, and Composer. Failures are warnings, since PHP itself works.
+func finishInstall(version, installDir string) {
+	// Create the version's php.ini and conf.d
+	if err := ensureINI(version, installINIPreset); err != nil {
+		fmt.Printf("⚠️  Warning: Failed to create php.ini: %v\n", err)
+	}
 
 	// Install Composer
-	if err := installComposer(phpVersion.Version, installDir); err != nil {
+	refreshComposerCatalogIfStale()
+	if err := installComposer(version, installDir); err != nil {
 		fmt.Printf("⚠️  Warning: Failed to install Composer: %v\n", err)
 		fmt.Printf("You can install Composer manually later\n")
 	} else {
@@ -116,62 +202,147 @@ func installPHP(version string) error {
 	}
 
 	fmt.Printf("Use 'phpvm switch %s' to switch to this version\n", version)
+}
+
+// installPHPFrom installs PHP from a local file or URL and sets it up like
+// a catalog install
+func installPHPFrom(location, label, checksum string) error {
+	fmt.Printf("Preparing to install PHP from %s...\n", location)
+	version, err := installFromLocation(location, label, checksum)
+	if err != nil {
+		return err
+	}
+	warnIfEOL(version)
 
+	installDir, err := versionDir(version)
+	if err != nil {
+		return err
+	}
+	finishInstall(version, installDir)
 	return nil
 }
 
 // installComposer downloads and installs Composer for the PHP version
 func installComposer(phpVersion string, phpInstallDir string) error {
-	// Find compatible Composer version
-	composerVersion := data.GetCompatibleComposerVersion(phpVersion)
-	if composerVersion == nil {
-		return fmt.Errorf("no compatible Composer version found for PHP %s", phpVersion)
+	// Find the pinned or newest compatible Composer version
+	composerVersion, _, err := versionComposerVersion(phpVersion)
+	if err != nil {
+		return err
+	}
+
+	composerPharPath, err := ensureComposerPhar(composerVersion)
+	if err != nil {
+		return err
+	}
+
+	// Create a composer wrapper script in the PHP installation directory
+	composerScript := filepath.Join(phpInstallDir, "composer")
+	if err := writeComposerWrapper(composerScript, phpVersion, composerPharPath); err != nil {
+		return fmt.Errorf("failed to create Composer script: %v", err)
+	}
+
+	fmt.Printf("Composer %s linked to PHP %s\n", composerVersion.Version, phpVersion)
+	return nil
+}
+
+// ensureComposerPhar downloads a Composer release into ~/.phpvm/composer
+// unless it is already there, and returns the path of its phar
+func ensureComposerPhar(composerVersion *data.ComposerVersion) (string, error) {
+	if err := checkComposerVersionName(composerVersion.Version); err != nil {
+		return "", err
 	}
 
 	// Create Composer installation directory structure
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
-		return fmt.Errorf("failed to get user home directory: %v", err)
+		return "", fmt.Errorf("failed to get user home directory: %v", err)
 	}
 
 	composerBaseDir := filepath.Join(homeDir, ".phpvm", "composer")
 	composerVersionDir := filepath.Join(composerBaseDir, composerVersion.Version)
-	
+
 	// Create directories if they don't exist
 	if err := os.MkdirAll(composerVersionDir, 0755); err != nil {
-		return fmt.Errorf("failed to create Composer directory: %v", err)
+		return "", fmt.Errorf("failed to create Composer directory: %v", err)
 	}
 
 	composerPharPath := filepath.Join(composerVersionDir, "composer.phar")
-	
-	// Check if Composer is already installed
+
+	// Check if Composer is already installed, verifying it before reuse
 	if _, err := os.Stat(composerPharPath); err == nil {
-		fmt.Printf("Composer %s already installed, creating symlink...\n", composerVersion.Version)
-	} else {
-		// Download Composer
-		fmt.Printf("Downloading Composer %s from %s...\n", composerVersion.Version, composerVersion.URL)
-		if err := downloadFile(composerVersion.URL, composerPharPath); err != nil {
-			return fmt.Errorf("failed to download Composer: %v", err)
+		if err := verifyInstalledComposer(composerVersion, composerPharPath); err != nil {
+			return "", fmt.Errorf("refusing to link Composer %s: %v", composerVersion.Version, err)
 		}
+		return composerPharPath, nil
+	}
 
-		// Make Composer executable
-		if err := os.Chmod(composerPharPath, 0755); err != nil {
-			return fmt.Errorf("failed to make Composer executable: %v", err)
-		}
+	// Download Composer next to its final location and only move it into
+	// place once it has been verified. Parallel installs can fetch the same
+	// release, so each download gets its own name.
+	download, err := os.CreateTemp(composerVersionDir, "composer.phar.download-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create Composer download: %v", err)
+	}
+	download.Close()
+	downloadPath := download.Name()
+	fmt.Printf("Downloading Composer %s from %s...\n", composerVersion.Version, composerVersion.URL)
+	if err := cachedDownload(composerVersion.URL, downloadPath); err != nil {
+		os.Remove(downloadPath)
+		return "", fmt.Errorf("failed to download Composer: %v", err)
 	}
 
-	// Create a composer wrapper script in the PHP installation directory
-	composerScript := filepath.Join(phpInstallDir, "composer")
-	scriptContent := fmt.Sprintf("#!/bin/bash\n%s %s \"$@\"\n", 
-		filepath.Join(phpInstallDir, "php"), 
-		composerPharPath)
+	checksum, err := composerChecksum(composerVersion)
+	if err != nil {
+		os.Remove(downloadPath)
+		return "", err
+	}
+	if err := verifyComposerPhar(downloadPath, checksum); err != nil {
+		os.Remove(downloadPath)
+		return "", fmt.Errorf("refusing to install Composer %s: %v", composerVersion.Version, err)
+	}
+	fmt.Printf("✅ Verified Composer %s (sha256 %s)\n", composerVersion.Version, checksum)
 
-	if err := os.WriteFile(composerScript, []byte(scriptContent), 0755); err != nil {
-		return fmt.Errorf("failed to create Composer script: %v", err)
+	// Make Composer executable
+	if err := os.Chmod(downloadPath, 0755); err != nil {
+		return "", fmt.Errorf("failed to make Composer executable: %v", err)
+	}
+	if err := os.Rename(downloadPath, composerPharPath); err != nil {
+		return "", fmt.Errorf("failed to install Composer: %v", err)
 	}
 
-	fmt.Printf("Composer %s linked to PHP %s\n", composerVersion.Version, phpVersion)
-	return nil
+	// Remember the checksum so the phar can be re-verified offline
+	if err := os.WriteFile(composerPharPath+".sha256", []byte(checksum+"\n"), 0644); err != nil {
+		fmt.Printf("⚠️  Warning: Failed to store Composer checksum: %v\n", err)
+	}
+
+	return composerPharPath, nil
+}
+
+// verifyInstalledComposer re-checks a phar that is already on disk against
+// the catalog checksum or the one recorded when it was downloaded. Phars
+// downloaded before verification existed are checked against the published
+// checksum, and refused when it can't be fetched.
+func verifyInstalledComposer(composerVersion *data.ComposerVersion, pharPath string) error {
+	checksum := composerVersion.SHA256
+	if checksum == "" {
+		if recorded, err := os.ReadFile(pharPath + ".sha256"); err == nil {
+			checksum = strings.TrimSpace(string(recorded))
+		}
+	}
+
+	if checksum == "" {
+		fetched, err := composerChecksum(composerVersion)
+		if err != nil {
+			return fmt.Errorf("could not verify it: %v", err)
+		}
+		checksum = fetched
+		if err := verifyComposerPhar(pharPath, checksum); err != nil {
+			return err
+		}
+		return os.WriteFile(pharPath+".sha256", []byte(checksum+"\n"), 0644)
+	}
+
+	return verifyComposerPhar(pharPath, checksum)
 }
 
 // downloadFile downloads a file from URL to the specified path
@@ -0,0 +1,197 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/yourusername/phpvm/data"
+)
+
+// installFromLocation installs a PHP that doesn't come from the catalog: a
+// php binary or a tarball of an install prefix (containing bin/php), given
+// as a local path or an http(s) URL. The version is detected by running
+// the binary unless a label is given. It returns the installed version.
+func installFromLocation(location, label, checksum string) (string, error) {
+	if label != "" && (label != filepath.Base(label) || strings.HasPrefix(label, ".") || label == systemVersion) {
+		return "", fmt.Errorf("invalid version name %q", label)
+	}
+	if label != "" && isVersionInstalled(label) {
+		return "", fmt.Errorf("PHP %s is already installed. Uninstall it first or pick another version name", label)
+	}
+
+	root, err := phpvmHome()
+	if err != nil {
+		return "", err
+	}
+	stagingRoot := filepath.Join(root, "staging")
+	if err := os.MkdirAll(stagingRoot, 0755); err != nil {
+		return "", fmt.Errorf("failed to create %s: %v", stagingRoot, err)
+	}
+	staging, err := os.MkdirTemp(stagingRoot, "install-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create staging directory: %v", err)
+	}
+	defer os.RemoveAll(staging)
+
+	source := location
+	if isURL(location) {
+		source = filepath.Join(staging, "download")
+		fmt.Printf("Downloading %s...\n", location)
+		if err := cachedDownload(location, source); err != nil {
+			return "", fmt.Errorf("failed to download %s: %v", location, err)
+		}
+	} else {
+		if location, err = filepath.Abs(location); err != nil {
+			return "", fmt.Errorf("invalid path %s: %v", source, err)
+		}
+		source = location
+	}
+
+	if checksum != "" {
+		if err := verifySHA256(source, checksum); err != nil {
+			return "", fmt.Errorf("refusing to install %s: %v", location, err)
+		}
+		fmt.Printf("✅ Verified %s (sha256 %s)\n", filepath.Base(location), strings.ToLower(checksum))
+	} else if checksum, err = sha256File(source); err != nil {
+		return "", fmt.Errorf("failed to hash %s: %v", source, err)
+	}
+
+	prefix, err := stagePHP(source, filepath.Join(staging, "prefix"))
+	if err != nil {
+		return "", err
+	}
+	probe, err := probePHP(filepath.Join(prefix, "php"))
+	if err != nil {
+		return "", err
+	}
+	parsed, _ := data.ParseVersion(probe.Version)
+	if label == "" {
+		label = parsed.String()
+		if isVersionInstalled(label) {
+			return "", fmt.Errorf("PHP %s is already installed. Uninstall it first or pass another version name", label)
+		}
+	} else if labelVersion, err := data.ParseVersion(label); err == nil && labelVersion.Compare(parsed) != 0 {
+		fmt.Printf("⚠️  Warning: %s reports PHP %s, but is being installed as %s\n", filepath.Base(location), parsed.String(), label)
+	}
+
+	installDir, err := versionDir(label)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(installDir), 0755); err != nil {
+		return "", fmt.Errorf("failed to create versions directory: %v", err)
+	}
+	if err := clearPartialInstall(installDir); err != nil {
+		return "", err
+	}
+	if err := os.Rename(prefix, installDir); err != nil {
+		return "", fmt.Errorf("failed to install PHP %s: %v", label, err)
+	}
+
+	meta, err := loadMetadata(label)
+	if err != nil {
+		return "", err
+	}
+	meta.From = &InstallSource{Location: location, SHA256: strings.ToLower(checksum), InstalledAt: time.Now().UTC()}
+	if err := saveMetadata(meta); err != nil {
+		return "", err
+	}
+
+	fmt.Printf("✅ PHP %s installed successfully to %s\n", label, installDir)
+	return label, nil
+}
+
+// stagePHP puts the php binary or unpacked prefix tarball at source into
+// dir and returns the directory that should become the version directory,
+// with its php entry pointing at the binary
+func stagePHP(source, dir string) (string, error) {
+	kind, err := detectArchive(source)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create staging directory: %v", err)
+	}
+
+	switch kind {
+	case "":
+		if err := copyFile(source, filepath.Join(dir, "php"), 0755); err != nil {
+			return "", fmt.Errorf("failed to copy %s: %v", source, err)
+		}
+		return dir, nil
+	case "gzip":
+		err = extractTarGz(source, dir)
+	case "tar":
+		var file *os.File
+		if file, err = os.Open(source); err == nil {
+			err = extractTar(file, dir)
+			file.Close()
+		}
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to extract %s: %v", source, err)
+	}
+
+	prefix, err := findPrefix(dir)
+	if err != nil {
+		return "", fmt.Errorf("%s: %v", source, err)
+	}
+	// Point php at bin/php the same way source builds do
+	if _, err := os.Lstat(filepath.Join(prefix, "php")); err != nil {
+		if err := os.Symlink(filepath.Join("bin", "php"), filepath.Join(prefix, "php")); err != nil {
+			return "", fmt.Errorf("failed to link PHP binary: %v", err)
+		}
+	}
+	return prefix, nil
+}
+
+// findPrefix returns the install prefix inside an unpacked tarball: the
+// directory itself, or its only subdirectory, holding bin/php (or php)
+func findPrefix(dir string) (string, error) {
+	candidates := []string{dir}
+	if entries, err := os.ReadDir(dir); err == nil && len(entries) == 1 && entries[0].IsDir() {
+		candidates = append(candidates, filepath.Join(dir, entries[0].Name()))
+	}
+	for _, candidate := range candidates {
+		for _, binary := range []string{filepath.Join("bin", "php"), "php"} {
+			if info, err := os.Stat(filepath.Join(candidate, binary)); err == nil && !info.IsDir() {
+				return candidate, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("no bin/php found in the archive")
+}
+
+// detectArchive reports whether a file is a gzip-compressed or plain tar
+// archive by its content, returning an empty string for anything else
+func detectArchive(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	header := make([]byte, 512)
+	n, err := io.ReadFull(file, header)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", fmt.Errorf("failed to read %s: %v", path, err)
+	}
+	header = header[:n]
+	switch {
+	case bytes.HasPrefix(header, []byte{0x1f, 0x8b}):
+		return "gzip", nil
+	case len(header) >= 262 && bytes.Equal(header[257:262], []byte("ustar")):
+		return "tar", nil
+	}
+	return "", nil
+}
+
+// isURL reports whether location is an http(s) URL rather than a path
+func isURL(location string) bool {
+	return strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://")
+}
@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+// installResult is the outcome of one install in a parallel run
+type installResult struct {
+	Request string
+	Version string
+	Err     error
+}
+
+// readVersionList reads the versions in a file, one or more per line, with
+// # starting a comment
+func readVersionList(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %v", path, err)
+	}
+	defer file.Close()
+
+	var versions []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		versions = append(versions, strings.Fields(line)...)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", path, err)
+	}
+	return versions, nil
+}
+
+// catalogVersion turns an install request such as "8.3" into the newest
+// matching catalog version, leaving it unchanged when nothing matches so
+// installPHP reports it
+func catalogVersion(request string) string {
+	if match, err := bestCatalogMatch(request); err == nil && match != "" {
+		return match
+	}
+	return request
+}
+
+// installMany installs several versions, running up to jobs installs at
+// once. Each install runs as its own phpvm process so its output can be
+// prefixed with the version; a summary follows, and an error is returned
+// if any install failed.
+func installMany(requests []string, jobs int) error {
+	if jobs < 1 {
+		return fmt.Errorf("--jobs must be at least 1")
+	}
+	self, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate the phpvm executable: %v", err)
+	}
+
+	var results []*installResult
+	seen := map[string]bool{}
+	width := 0
+	for _, request := range requests {
+		version := catalogVersion(request)
+		if seen[version] {
+			continue
+		}
+		seen[version] = true
+		results = append(results, &installResult{Request: request, Version: version})
+		width = max(width, len(version))
+	}
+
+	// Refresh the Composer catalog once rather than in every install
+	refreshComposerCatalogIfStale()
+	fmt.Printf("Installing %d PHP version(s), %d at a time...\n", len(results), min(jobs, len(results)))
+
+	out := &prefixedOutput{w: os.Stdout}
+	queue := make(chan *installResult)
+	var wg sync.WaitGroup
+	for i := 0; i < min(jobs, len(results)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for result := range queue {
+				prefix := fmt.Sprintf("[%-*s] ", width, result.Version)
+				result.Err = runInstallProcess(self, result.Version, prefix, out)
+			}
+		}()
+	}
+	for _, result := range results {
+		queue <- result
+	}
+	close(queue)
+	wg.Wait()
+
+	fmt.Println("\nSummary:")
+	failed := 0
+	for _, result := range results {
+		name := result.Version
+		if result.Request != result.Version {
+			name = fmt.Sprintf("%s (%s)", result.Version, result.Request)
+		}
+		if result.Err != nil {
+			fmt.Printf("  ❌ %s: %v\n", name, result.Err)
+			failed++
+			continue
+		}
+		fmt.Printf("  ✅ %s\n", name)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d install(s) failed", failed, len(results))
+	}
+	return nil
+}
+
+// runInstallProcess runs 'phpvm install <version>' and copies its output,
+// line by line with a prefix, to out. The error is the one the install
+// reported.
+func runInstallProcess(self, version, prefix string, out *prefixedOutput) error {
+	cmd := exec.Command(self, "install", "--ini-preset", installINIPreset, version)
+	pipe, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	cmd.Stderr = cmd.Stdout
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start install: %v", err)
+	}
+
+	lastError := ""
+	scanner := bufio.NewScanner(pipe)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if message, ok := strings.CutPrefix(line, "Error: "); ok {
+			lastError = message
+		}
+		out.println(prefix + line)
+	}
+	io.Copy(io.Discard, pipe)
+
+	if err := cmd.Wait(); err != nil {
+		if lastError != "" {
+			return fmt.Errorf("%s", lastError)
+		}
+		return err
+	}
+	return nil
+}
+
+// prefixedOutput writes whole lines from concurrent installs so they don't
+// interleave mid-line
+type prefixedOutput struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (p *prefixedOutput) println(line string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	fmt.Fprintln(p.w, line)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 
 	"github.com/spf13/cobra"
@@ -16,43 +17,109 @@ var listCmd = &cobra.Command{
 	Long: `List all PHP versions that are available for installation.
 This fetches the list of available versions from the official PHP.net website.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return listAvailableVersions()
+		return listAvailableVersions(listWithExtFlag, listPreFlag)
 	},
 }
 
+var (
+	listWithExtFlag string
+	listPreFlag     bool
+)
+
 func init() {
+	listCmd.Flags().BoolVar(&listPreFlag, "pre", false, "include pre-releases (alpha, beta, RC) and nightly builds")
+	listCmd.Flags().StringVar(&listWithExtFlag, "with-ext", "", "only show installed versions that provide this extension")
 	RootCmd.AddCommand(listCmd)
 }
 
-func listAvailableVersions() error {
-	versions := data.AvailableVersions
+func listAvailableVersions(withExt string, pre bool) error {
+	versions := slices.Clone(data.AvailableVersions)
+
+	// Installed versions missing from the catalog, such as imported ones
+	installed, err := installedVersions()
+	if err != nil {
+		return err
+	}
+	for _, version := range installed {
+		if data.FindPHPVersion(version) == nil {
+			versions = append(versions, data.PHPVersion{Version: version})
+		}
+	}
 
-	// Sort versions by release date (newest first)
-	sort.Slice(versions, func(i, j int) bool {
-		return versions[i].Released.After(versions[j].Released)
+	// Sort versions newest first; release dates would misorder backported patches
+	sort.SliceStable(versions, func(i, j int) bool {
+		return data.CompareVersions(versions[i].Version, versions[j].Version) > 0
 	})
 
 	fmt.Println("Available PHP versions:")
-	fmt.Printf("%-12s %-12s\n", "Version", "Released")
-	fmt.Println("----------------------------------")
+	fmt.Printf("%-12s %-12s %-16s\n", "Version", "Released", "Support")
+	fmt.Println("---------------------------------------------")
 	
 	for _, v := range versions {
+		if withExt != "" && !versionHasExtension(v.Version, withExt) {
+			continue
+		}
+		if v.IsPreRelease() && !pre {
+			continue
+		}
+
 		status := " "
 		if isVersionInstalled(v.Version) {
 			status = "*"
 		}
 		
-		fmt.Printf("%-12s %-12s\n",
+		fmt.Printf("%-12s %-12s %-16s%s\n",
 			status + v.Version, 
-			v.Released.Format("2006-01-02"))
+			releaseLabel(v),
+			supportLabel(v.Version),
+			versionNote(v))
 	}
 	
 	fmt.Println("\nUse 'phpvm install <version>' to install a specific version")
 	fmt.Println("* = Already installed")
+	if !pre {
+		fmt.Println("Use --pre to include pre-releases and nightly builds")
+	}
 
 	return nil
 }
 
+// releaseLabel returns the release date shown for a version. Nightly builds
+// show when the installed build was made, if there is one.
+func releaseLabel(v data.PHPVersion) string {
+	if !v.Released.IsZero() {
+		return v.Released.Format("2006-01-02")
+	}
+	if isVersionInstalled(v.Version) {
+		if meta, err := loadMetadata(v.Version); err == nil && meta.Build != nil {
+			return meta.Build.BuiltAt.Format("2006-01-02")
+		}
+	}
+	return "-"
+}
+
+// versionNote marks imported, pre-release and source-built entries in the
+// list
+func versionNote(v data.PHPVersion) string {
+	if isVersionInstalled(v.Version) {
+		if meta, err := loadMetadata(v.Version); err == nil && meta.Imported != nil {
+			return " imported from " + meta.Imported.Binary
+		} else if err == nil && meta.From != nil {
+			return " installed from " + meta.From.Location
+		}
+	}
+
+	switch {
+	case v.Version == data.NightlyVersion:
+		return " pre-release, built from php-src master"
+	case v.IsPreRelease() && v.IsSourceBuild():
+		return " pre-release, built from source"
+	case v.IsPreRelease():
+		return " pre-release"
+	}
+	return ""
+}
+
 // isVersionInstalled checks if a specific PHP version is installed
 func isVersionInstalled(version string) bool {
 	homeDir, err := os.UserHomeDir()
@@ -69,3 +136,16 @@ func isVersionInstalled(version string) bool {
 	
 	return true
 }
+
+// versionHasExtension reports whether an installed version provides an
+// extension, using the cached module scan from 'phpvm ext list'
+func versionHasExtension(version, extension string) bool {
+	if !isVersionInstalled(version) {
+		return false
+	}
+	scan, err := moduleScan(version, false)
+	if err != nil {
+		return false
+	}
+	return slices.Contains(scan.Loaded, normalizeModuleName(extension))
+}
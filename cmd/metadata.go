@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// metadataFile is the name of the per-version metadata file
+const metadataFile = ".phpvm.json"
+
+// VersionMetadata is the state phpvm keeps alongside each installed version
+type VersionMetadata struct {
+	Version    string                    `json:"version"`
+	Extensions map[string]ExtensionState `json:"extensions,omitempty"`
+	Modules    *ModuleScan               `json:"modules,omitempty"`
+	Composer   string                    `json:"composer,omitempty"`
+	Build      *SourceBuild              `json:"build,omitempty"`
+	Imported   *ImportedInstall          `json:"imported,omitempty"`
+	From       *InstallSource            `json:"from,omitempty"`
+}
+
+// InstallSource records a version installed with --from instead of from
+// the catalog
+type InstallSource struct {
+	Location    string    `json:"location"` // Local path or URL
+	SHA256      string    `json:"sha256"`
+	InstalledAt time.Time `json:"installed_at"`
+}
+
+// ImportedInstall records a PHP binary phpvm links to but doesn't own, such
+// as a distribution package
+type ImportedInstall struct {
+	Binary  string `json:"binary"`
+	INI     string `json:"ini,omitempty"`      // php.ini the binary loads on its own
+	ScanDir string `json:"scan_dir,omitempty"` // Its own conf.d, which loads packaged extensions
+}
+
+// SourceBuild records where a version compiled from source came from
+type SourceBuild struct {
+	URL     string    `json:"url"`
+	BuiltAt time.Time `json:"built_at"`
+}
+
+// ExtensionState records an extension built and enabled by phpvm
+type ExtensionState struct {
+	Version string `json:"version"`
+	Path    string `json:"path"`
+	Zend    bool   `json:"zend,omitempty"`
+	Enabled bool   `json:"enabled"`
+}
+
+// ModuleScan caches the extensions reported by a version's binary
+type ModuleScan struct {
+	Loaded    []string  `json:"loaded"`
+	Loadable  []string  `json:"loadable,omitempty"`
+	ScannedAt time.Time `json:"scanned_at"`
+}
+
+// loadMetadata reads the metadata for an installed PHP version.
+// A missing file yields empty metadata rather than an error.
+func loadMetadata(version string) (*VersionMetadata, error) {
+	dir, err := versionDir(version)
+	if err != nil {
+		return nil, err
+	}
+
+	meta := &VersionMetadata{Version: version}
+	content, err := os.ReadFile(filepath.Join(dir, metadataFile))
+	if os.IsNotExist(err) {
+		return meta, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read metadata for PHP %s: %v", version, err)
+	}
+
+	if err := json.Unmarshal(content, meta); err != nil {
+		return nil, fmt.Errorf("failed to parse metadata for PHP %s: %v", version, err)
+	}
+	return meta, nil
+}
+
+// saveMetadata writes the metadata for an installed PHP version
+func saveMetadata(meta *VersionMetadata) error {
+	dir, err := versionDir(meta.Version)
+	if err != nil {
+		return err
+	}
+
+	content, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode metadata: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, metadataFile), append(content, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write metadata for PHP %s: %v", meta.Version, err)
+	}
+	return nil
+}
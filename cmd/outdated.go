@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/yourusername/phpvm/data"
+)
+
+var outdatedCmd = &cobra.Command{
+	Use:   "outdated",
+	Short: "List installed versions with a newer patch release",
+	Long: `Compare every installed PHP version with the newest release of its branch
+in the catalog, e.g. 8.3.12 with 8.3.13. Use 'phpvm upgrade' to move to the
+new patch releases.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return listOutdated()
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(outdatedCmd)
+}
+
+// PatchUpgrade is an installed version and the newer patch release of its
+// branch
+type PatchUpgrade struct {
+	Installed string
+	Latest    string
+}
+
+// outdatedVersions returns the installed stable versions that have a newer
+// patch release in the catalog
+func outdatedVersions() ([]PatchUpgrade, error) {
+	installed, err := installedVersions()
+	if err != nil {
+		return nil, err
+	}
+
+	var upgrades []PatchUpgrade
+	for _, version := range installed {
+		parsed, err := data.ParseVersion(version)
+		if err != nil || parsed.IsPreRelease() {
+			continue
+		}
+		// Imported binaries are upgraded by the package manager that owns them
+		if meta, err := loadMetadata(version); err == nil && meta.Imported != nil {
+			continue
+		}
+		latest := data.LatestPatch(version)
+		if latest != nil && data.CompareVersions(latest.Version, version) > 0 {
+			upgrades = append(upgrades, PatchUpgrade{Installed: version, Latest: latest.Version})
+		}
+	}
+	return upgrades, nil
+}
+
+func listOutdated() error {
+	upgrades, err := outdatedVersions()
+	if err != nil {
+		return err
+	}
+	if len(upgrades) == 0 {
+		fmt.Println("✅ All installed versions are on the latest patch release")
+		return nil
+	}
+
+	fmt.Printf("%-12s %-12s %s\n", "Installed", "Latest", "Support")
+	fmt.Println("---------------------------------------------")
+	for _, upgrade := range upgrades {
+		latest := upgrade.Latest
+		if isVersionInstalled(latest) {
+			latest += "*"
+		}
+		fmt.Printf("%-12s %-12s %s\n", upgrade.Installed, latest, supportLabel(upgrade.Installed))
+	}
+	fmt.Println("\n* = Already installed")
+	fmt.Println("Use 'phpvm upgrade [branch]' to install the new patch releases")
+	return nil
+}
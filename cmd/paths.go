@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/yourusername/phpvm/data"
+)
+
+// phpvmHome returns the root directory phpvm keeps its state in (~/.phpvm)
+func phpvmHome() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %v", err)
+	}
+	return filepath.Join(homeDir, ".phpvm"), nil
+}
+
+// versionDir returns the installation directory for a PHP version
+func versionDir(version string) (string, error) {
+	root, err := phpvmHome()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(root, "versions", version), nil
+}
+
+// installedVersionDir returns the installation directory for a PHP version,
+// failing when that version has not been installed yet
+func installedVersionDir(version string) (string, error) {
+	dir, err := versionDir(version)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(filepath.Join(dir, "php")); err != nil {
+		return "", fmt.Errorf("PHP version %s is not installed. Use 'phpvm install %s' first", version, version)
+	}
+	return dir, nil
+}
+
+// activeVersion returns the PHP version currently linked into ~/.phpvm/bin
+func activeVersion() (string, error) {
+	root, err := phpvmHome()
+	if err != nil {
+		return "", err
+	}
+
+	if content, err := os.ReadFile(filepath.Join(root, "version")); err == nil {
+		if version := strings.TrimSpace(string(content)); version != "" {
+			return version, nil
+		}
+	}
+
+	// Installs switched by older phpvm releases only have the php symlink
+	target, err := os.Readlink(filepath.Join(root, "bin", "php"))
+	if err != nil {
+		return "", fmt.Errorf("no active PHP version. Use 'phpvm switch <version>' first")
+	}
+
+	// The symlink points at ~/.phpvm/versions/<version>/php
+	version := filepath.Base(filepath.Dir(target))
+	if !strings.HasPrefix(target, filepath.Join(root, "versions")) || version == "" {
+		return "", fmt.Errorf("unexpected PHP symlink target: %s", target)
+	}
+	return version, nil
+}
+
+// saveActiveVersion records the PHP version selected with 'phpvm switch'
+func saveActiveVersion(version string) error {
+	root, err := phpvmHome()
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(root, "version"), []byte(version+"\n"), 0644); err != nil {
+		return fmt.Errorf("failed to record active version: %v", err)
+	}
+	return nil
+}
+
+// installedVersions returns the PHP versions present under ~/.phpvm/versions
+func installedVersions() ([]string, error) {
+	root, err := phpvmHome()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(filepath.Join(root, "versions"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read versions directory: %v", err)
+	}
+
+	var versions []string
+	for _, entry := range entries {
+		if entry.IsDir() && isVersionInstalled(entry.Name()) {
+			versions = append(versions, entry.Name())
+		}
+	}
+	data.SortVersions(versions)
+	return versions, nil
+}
+
+// checkBundledName rejects a version name from a bundle or other untrusted
+// source unless it is a PHP version (or catalog name like "nightly") that
+// stays inside the directory it is joined to
+func checkBundledName(name string) error {
+	if name == "" || strings.ContainsAny(name, `/\`) || strings.Contains(name, "..") || strings.HasPrefix(name, ".") {
+		return fmt.Errorf("invalid version name %q", name)
+	}
+	if _, err := data.ParseVersion(name); err != nil && data.FindPHPVersion(name) == nil {
+		return fmt.Errorf("invalid version name %q", name)
+	}
+	return nil
+}
+
+// checkComposerVersionName rejects a Composer version that isn't a version
+// number, since it names a directory under ~/.phpvm/composer and goes into
+// the download URL
+func checkComposerVersionName(version string) error {
+	if _, err := data.ParseVersion(version); err != nil || strings.ContainsAny(version, `/\?#%`) || strings.Contains(version, "..") {
+		return fmt.Errorf("invalid Composer version %q", version)
+	}
+	return nil
+}
+
+// clearPartialInstall removes a version directory left behind by an
+// interrupted install. Anything else there, such as a directory that still
+// has its php binary or a symlink, is refused rather than removed.
+func clearPartialInstall(dir string) error {
+	info, err := os.Lstat(dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %v", dir, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s exists and is not a directory", dir)
+	}
+	if _, err := os.Lstat(filepath.Join(dir, "php")); err == nil {
+		return fmt.Errorf("%s already holds a PHP install", dir)
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("failed to clear %s: %v", dir, err)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ProjectSettings holds the pins phpvm keeps for a project directory
+type ProjectSettings struct {
+	Composer string `json:"composer,omitempty"`
+}
+
+// projectsPath returns the file holding per-project settings
+func projectsPath() (string, error) {
+	root, err := phpvmHome()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(root, "projects.json"), nil
+}
+
+// loadProjects reads the per-project settings keyed by absolute directory
+func loadProjects() (map[string]ProjectSettings, error) {
+	path, err := projectsPath()
+	if err != nil {
+		return nil, err
+	}
+
+	projects := map[string]ProjectSettings{}
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return projects, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", path, err)
+	}
+	if err := json.Unmarshal(content, &projects); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", path, err)
+	}
+	return projects, nil
+}
+
+// saveProjects writes the per-project settings
+func saveProjects(projects map[string]ProjectSettings) error {
+	path, err := projectsPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %v", filepath.Dir(path), err)
+	}
+
+	content, err := json.MarshalIndent(projects, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode project settings: %v", err)
+	}
+	if err := os.WriteFile(path, append(content, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %v", path, err)
+	}
+	return nil
+}
+
+// findProjectSettings returns the settings of the nearest project at or
+// above dir, along with that project's directory
+func findProjectSettings(dir string) (ProjectSettings, string, error) {
+	projects, err := loadProjects()
+	if err != nil {
+		return ProjectSettings{}, "", err
+	}
+	for {
+		if settings, ok := projects[dir]; ok {
+			return settings, dir, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ProjectSettings{}, "", nil
+		}
+		dir = parent
+	}
+}
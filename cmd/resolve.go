@@ -0,0 +1,225 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/yourusername/phpvm/data"
+)
+
+// versionFile is the per-project file that pins a PHP version
+const versionFile = ".php-version"
+
+// Resolution describes which PHP version applies to a directory and why
+type Resolution struct {
+	// Version is the installed version selected, empty if none matched
+	Version string
+	// Request is what the source asked for, e.g. "8.3" or "^8.2"
+	Request string
+	// Source names the file or setting the request came from
+	Source string
+	// Alias is the alias name the request went through, if any
+	Alias string
+}
+
+// resolveVersion determines the PHP version for dir: the session version set
+// with 'phpvm shell', a .php-version file, then composer.json's
+// config.platform.php and require.php, and finally the version selected with
+// 'phpvm switch'
+func resolveVersion(dir string) (*Resolution, error) {
+	if request := os.Getenv(sessionVersionEnv); request != "" {
+		return matchInstalled(request, sessionVersionEnv+" (phpvm shell)")
+	}
+
+	resolution, err := resolveProjectVersion(dir)
+	if err != nil || resolution != nil {
+		return resolution, err
+	}
+
+	version, err := activeVersion()
+	if err != nil {
+		return nil, err
+	}
+	root, err := phpvmHome()
+	if err != nil {
+		return nil, err
+	}
+	return &Resolution{Version: version, Request: version, Source: filepath.Join(root, "version")}, nil
+}
+
+// resolveProjectVersion resolves the version requested by the project that
+// contains dir, returning nil when the project doesn't request one
+func resolveProjectVersion(dir string) (*Resolution, error) {
+	if path, found := findUpwards(dir, versionFile); found {
+		request, err := readVersionFile(path)
+		if err != nil {
+			return nil, err
+		}
+		return matchInstalled(request, path)
+	}
+
+	composer, err := findComposerJSON(dir)
+	if err != nil || composer == nil {
+		return nil, err
+	}
+
+	// config.platform.php pins the exact version Composer resolves for, so
+	// prefer an installed version from that branch
+	if platform := strings.TrimSpace(composer.Config.Platform["php"]); platform != "" {
+		request := platform
+		if v, err := data.ParseVersion(platform); err == nil && v.Parts() >= 2 {
+			request = v.Branch()
+		}
+		return matchInstalled(request, composer.Path+" (config.platform.php)")
+	}
+
+	if constraint := composer.PHPConstraint(); constraint != "" {
+		return matchInstalled(constraint, composer.Path+" (require.php)")
+	}
+	return nil, nil
+}
+
+// targetVersion returns the installed version a command should operate on:
+// the one passed with --version (which may be partial, like "8.3"), or the
+// one resolved for the current directory
+func targetVersion(flagValue string) (string, error) {
+	version, source, err := targetResolution(flagValue)
+	if err == nil && version == systemVersion {
+		return "", fmt.Errorf("the system PHP (selected by %s) isn't managed by phpvm. Use --version to pick a phpvm version", source)
+	}
+	return version, err
+}
+
+// targetResolution is targetVersion without rejecting the system PHP; it
+// also returns what selected the version
+func targetResolution(flagValue string) (version, source string, err error) {
+	var resolution *Resolution
+	if flagValue != "" {
+		resolution, err = matchInstalled(flagValue, "--version")
+	} else {
+		var cwd string
+		if cwd, err = os.Getwd(); err != nil {
+			return "", "", fmt.Errorf("failed to get current directory: %v", err)
+		}
+		resolution, err = resolveVersion(cwd)
+	}
+	if err != nil {
+		return "", "", err
+	}
+	version, err = requireResolved(resolution, false)
+	return version, resolution.Source, err
+}
+
+// readVersionFile reads the version request from a .php-version file
+func readVersionFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %v", path, err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			return line, nil
+		}
+	}
+	return "", fmt.Errorf("%s is empty", path)
+}
+
+// matchInstalled picks the newest installed version satisfying a request.
+// Like bestCatalogMatch, it only picks a pre-release when asked for by name.
+func matchInstalled(request, source string) (*Resolution, error) {
+	resolution := &Resolution{Request: request, Source: source}
+
+	target, err := resolveAlias(request)
+	if err != nil {
+		return nil, err
+	}
+	if target != "" {
+		resolution.Alias = request
+		resolution.Request = target
+		request = target
+	}
+
+	if request == systemVersion || isVersionInstalled(request) {
+		resolution.Version = request
+		return resolution, nil
+	}
+
+	installed, err := installedVersions()
+	if err != nil {
+		return nil, err
+	}
+	// As with the catalog, pre-releases and nightly are only used when named
+	var stable []string
+	for _, v := range installed {
+		if parsed, err := data.ParseVersion(v); err == nil && !parsed.IsPreRelease() {
+			stable = append(stable, v)
+		}
+	}
+	version, err := data.NewestSatisfying(stable, data.VersionRequestConstraint(request))
+	if err != nil {
+		return nil, fmt.Errorf("invalid PHP version %q in %s: %v", request, source, err)
+	}
+	resolution.Version = version
+	return resolution, nil
+}
+
+// bestCatalogMatch returns the newest catalog version satisfying a request.
+// Pre-releases are only picked when asked for by name, like "8.5.0RC2".
+func bestCatalogMatch(request string) (string, error) {
+	if data.FindPHPVersion(request) != nil {
+		return request, nil
+	}
+
+	var versions []string
+	for _, v := range data.AvailableVersions {
+		if !v.IsPreRelease() {
+			versions = append(versions, v.Version)
+		}
+	}
+	return data.NewestSatisfying(versions, data.VersionRequestConstraint(request))
+}
+
+// requireResolved turns a resolution into an installed version, offering to
+// install the best catalog match when nothing installed satisfies it
+func requireResolved(resolution *Resolution, offerInstall bool) (string, error) {
+	if resolution.Version != "" {
+		return resolution.Version, nil
+	}
+
+	candidate, err := bestCatalogMatch(resolution.Request)
+	if err != nil {
+		return "", err
+	}
+	if candidate == "" {
+		return "", fmt.Errorf("no installed or available PHP version satisfies %q from %s", resolution.Request, resolution.Source)
+	}
+
+	if !offerInstall || !confirm(fmt.Sprintf("PHP %q from %s is not installed. Install PHP %s now?", resolution.Request, resolution.Source, candidate)) {
+		return "", fmt.Errorf("no installed PHP version satisfies %q from %s. Use 'phpvm install %s'", resolution.Request, resolution.Source, candidate)
+	}
+
+	if err := installPHP(candidate); err != nil {
+		return "", err
+	}
+	return candidate, nil
+}
+
+// confirm asks a yes/no question on the terminal, defaulting to no when
+// stdin is not interactive
+func confirm(question string) bool {
+	if info, err := os.Stdin.Stat(); err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return false
+	}
+
+	fmt.Printf("%s [y/N] ", question)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
@@ -0,0 +1,248 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// sessionVersionEnv is the variable 'phpvm shell' sets to pick a version
+// for one shell session
+const sessionVersionEnv = "PHPVM_VERSION"
+
+var (
+	shellUnsetFlag bool
+	shellEmitFlag  string
+)
+
+var shellCmd = &cobra.Command{
+	Use:   "shell [version]",
+	Short: "Use a PHP version in the current shell session only",
+	Long: `Use a PHP version in the current shell session only, overriding the
+project's .php-version file and the global default without affecting other
+terminals. Use --unset to go back to the usual resolution.
+
+With the shell integration loaded (see 'phpvm init'), the current shell is
+updated in place. Otherwise a subshell is started with the version on its
+PATH; exit it to return.`,
+	Args:         cobra.MaximumNArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		switch {
+		case shellUnsetFlag:
+			return unsetShellVersion(shellEmitFlag)
+		case len(args) == 0:
+			return showShellVersion()
+		}
+		return setShellVersion(args[0], shellEmitFlag)
+	},
+}
+
+func init() {
+	shellCmd.Flags().BoolVar(&shellUnsetFlag, "unset", false, "stop using a session version")
+	shellCmd.Flags().StringVar(&shellEmitFlag, "emit", "", "print code for the given shell to evaluate (used by the shell integration)")
+	shellCmd.Flags().MarkHidden("emit")
+	RootCmd.AddCommand(shellCmd)
+}
+
+// versionShimDir returns a version's shims directory, holding php and
+// composer wrappers that can be put first on PATH
+func versionShimDir(version string) (string, error) {
+	if version == systemVersion {
+		return systemShimDir()
+	}
+	dir, err := versionDir(version)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "shims"), nil
+}
+
+// ensureVersionShims writes the php and composer wrappers of a version's
+// shims directory
+func ensureVersionShims(version string) (string, error) {
+	if version == systemVersion {
+		return ensureSystemShims()
+	}
+	installDir, err := installedVersionDir(version)
+	if err != nil {
+		return "", err
+	}
+	shimDir, err := versionShimDir(version)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(shimDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create %s: %v", shimDir, err)
+	}
+
+	if err := writePHPShim(filepath.Join(shimDir, "php"), version); err != nil {
+		return "", err
+	}
+	composer := filepath.Join(installDir, "composer")
+	if _, err := os.Stat(composer); err == nil {
+		if err := writeScript(filepath.Join(shimDir, "composer"), wrapperScript(nil, composer)); err != nil {
+			return "", err
+		}
+	}
+	return shimDir, nil
+}
+
+// sessionPATH returns PATH with any version shims and Composer vendor/bin
+// directories removed and shimDir, when given, put first. With isolated
+// Composer homes, the vendor/bin of version (or of the global default when
+// version is empty) follows it.
+func sessionPATH(version, shimDir string) (string, error) {
+	root, err := phpvmHome()
+	if err != nil {
+		return "", err
+	}
+	versionsDir := filepath.Join(root, "versions") + string(filepath.Separator)
+	systemShims, err := systemShimDir()
+	if err != nil {
+		return "", err
+	}
+	vendorBin, err := composerVendorBin(version)
+	if err != nil {
+		return "", err
+	}
+	globalVendorBin := filepath.Join(root, "composer-home", "vendor", "bin")
+	versionVendorBin := string(filepath.Separator) + filepath.Join("composer-home", "vendor", "bin")
+
+	entries := []string{}
+	for _, dir := range []string{shimDir, vendorBin} {
+		if dir != "" {
+			entries = append(entries, dir)
+		}
+	}
+	for _, entry := range filepath.SplitList(os.Getenv("PATH")) {
+		if entry == systemShims || entry == globalVendorBin {
+			continue
+		}
+		if strings.HasPrefix(entry, versionsDir) && (filepath.Base(entry) == "shims" || strings.HasSuffix(entry, versionVendorBin)) {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return strings.Join(entries, string(filepath.ListSeparator)), nil
+}
+
+// fishQuote quotes a string for fish
+func fishQuote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
+
+// emitEnv writes shell code setting (or, for empty values, unsetting)
+// environment variables
+func emitEnv(w io.Writer, shell string, keys []string, values map[string]string) error {
+	for _, key := range keys {
+		value := values[key]
+		switch shell {
+		case "fish":
+			if value == "" {
+				fmt.Fprintf(w, "set -e %s\n", key)
+				continue
+			}
+			if key == "PATH" {
+				fmt.Fprint(w, "set -gx PATH")
+				for _, entry := range filepath.SplitList(value) {
+					fmt.Fprint(w, " "+fishQuote(entry))
+				}
+				fmt.Fprintln(w)
+				continue
+			}
+			fmt.Fprintf(w, "set -gx %s %s\n", key, fishQuote(value))
+		case "bash", "zsh", "sh":
+			if value == "" {
+				fmt.Fprintf(w, "unset %s\n", key)
+				continue
+			}
+			fmt.Fprintf(w, "export %s=%s\n", key, shellQuote(value))
+		default:
+			return fmt.Errorf("unsupported shell %q (expected bash, zsh or fish)", shell)
+		}
+	}
+	return nil
+}
+
+func showShellVersion() error {
+	if version := os.Getenv(sessionVersionEnv); version != "" {
+		fmt.Fprintf(os.Stderr, "PHP %s is set for this shell session\n", version)
+		return nil
+	}
+	fmt.Fprintln(os.Stderr, "No session version set. Use 'phpvm shell <version>' to set one")
+	return nil
+}
+
+func setShellVersion(request, emit string) error {
+	resolution, err := matchInstalled(request, "the command line")
+	if err != nil {
+		return err
+	}
+	if resolution.Version == "" {
+		return fmt.Errorf("PHP version %s is not installed. Use 'phpvm install %s' first", request, request)
+	}
+	version := resolution.Version
+
+	shimDir, err := ensureVersionShims(version)
+	if err != nil {
+		return err
+	}
+	path, err := sessionPATH(version, shimDir)
+	if err != nil {
+		return err
+	}
+	warnIfEOL(version)
+
+	env := map[string]string{sessionVersionEnv: version, "PATH": path}
+	if emit != "" {
+		if version == systemVersion {
+			fmt.Fprintln(os.Stderr, "✅ Using the system PHP in this shell")
+		} else {
+			fmt.Fprintf(os.Stderr, "✅ Using PHP %s in this shell\n", version)
+		}
+		return emitEnv(os.Stdout, emit, []string{sessionVersionEnv, "PATH"}, env)
+	}
+
+	// Without the shell integration the parent shell can't be changed, so
+	// start a subshell instead
+	shell := os.Getenv("SHELL")
+	if shell == "" {
+		shell = "/bin/sh"
+	}
+	fmt.Printf("Starting a %s subshell with PHP %s. Type 'exit' to leave it\n", filepath.Base(shell), version)
+	fmt.Println("ℹ️  Load the shell integration with 'phpvm init' to switch the current shell instead")
+
+	sub := exec.Command(shell)
+	sub.Stdin, sub.Stdout, sub.Stderr = os.Stdin, os.Stdout, os.Stderr
+	sub.Env = envList(env)
+	if err := sub.Run(); err != nil {
+		if _, ok := err.(*exec.ExitError); !ok {
+			return fmt.Errorf("failed to start %s: %v", shell, err)
+		}
+	}
+	fmt.Printf("Left the PHP %s subshell\n", version)
+	return nil
+}
+
+func unsetShellVersion(emit string) error {
+	if emit == "" {
+		if version := os.Getenv(sessionVersionEnv); version != "" {
+			return fmt.Errorf("the shell integration isn't loaded. If this is a 'phpvm shell' subshell, type 'exit' to leave PHP %s", version)
+		}
+		return fmt.Errorf("no session version set")
+	}
+
+	path, err := sessionPATH("", "")
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(os.Stderr, "✅ Session version unset")
+	// Clearing the directory hook's version makes it re-apply on its next run
+	return emitEnv(os.Stdout, emit, []string{sessionVersionEnv, autoVersionEnv, "PATH"}, map[string]string{"PATH": path})
+}
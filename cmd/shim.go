@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// shellQuote quotes a string for safe use in a POSIX shell script
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// versionEnv returns the environment a PHP version runs with: its php.ini
+// and conf.d directory, and PHPVM_ACTIVE_VERSION for child tools. Imported
+// binaries keep scanning their own conf.d first, where packaged extensions
+// are loaded.
+func versionEnv(version string) (map[string]string, error) {
+	installDir, err := installedVersionDir(version)
+	if err != nil {
+		return nil, err
+	}
+	meta, err := loadMetadata(version)
+	if err != nil {
+		return nil, err
+	}
+
+	scanDir := filepath.Join(installDir, "conf.d")
+	if meta.Imported != nil && meta.Imported.ScanDir != "" {
+		scanDir = meta.Imported.ScanDir + string(filepath.ListSeparator) + scanDir
+	}
+	return map[string]string{
+		"PHPRC":                filepath.Join(installDir, "php.ini"),
+		"PHP_INI_SCAN_DIR":     scanDir,
+		"PHPVM_ACTIVE_VERSION": version,
+	}, nil
+}
+
+// composerEnv extends a version's environment with its COMPOSER_HOME when
+// isolated Composer homes are enabled
+func composerEnv(version string) (map[string]string, error) {
+	env, err := versionEnv(version)
+	if err != nil {
+		return nil, err
+	}
+	home, err := composerHome(version)
+	if err != nil {
+		return nil, err
+	}
+	if home != "" {
+		env["COMPOSER_HOME"] = home
+	}
+	return env, nil
+}
+
+// envList turns an environment map into KEY=value pairs replacing those in
+// the current environment
+func envList(env map[string]string) []string {
+	var list []string
+	for _, entry := range os.Environ() {
+		key, _, _ := strings.Cut(entry, "=")
+		if _, ok := env[key]; !ok {
+			list = append(list, entry)
+		}
+	}
+	for _, key := range sortedKeys(env) {
+		list = append(list, key+"="+env[key])
+	}
+	return list
+}
+
+// sortedKeys returns the keys of an environment map in a stable order
+func sortedKeys(env map[string]string) []string {
+	keys := make([]string, 0, len(env))
+	for key := range env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// phpCommand builds a command running a version's binary with the same ini
+// environment the php wrapper sets up
+func phpCommand(version string, args ...string) (*exec.Cmd, error) {
+	installDir, err := installedVersionDir(version)
+	if err != nil {
+		return nil, err
+	}
+	env, err := versionEnv(version)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := exec.Command(filepath.Join(installDir, "php"), args...)
+	cmd.Env = envList(env)
+	return cmd, nil
+}
+
+// wrapperScript renders a POSIX sh script that exports env and execs argv,
+// quoting every value so paths with spaces survive
+func wrapperScript(env map[string]string, argv ...string) string {
+	var b strings.Builder
+	b.WriteString("#!/bin/sh\n# Generated by phpvm. Do not edit.\n")
+	for _, key := range sortedKeys(env) {
+		fmt.Fprintf(&b, "%s=%s\nexport %s\n", key, shellQuote(env[key]), key)
+	}
+
+	b.WriteString("exec")
+	for _, arg := range argv {
+		b.WriteString(" " + shellQuote(arg))
+	}
+	b.WriteString(" \"$@\"\n")
+	return b.String()
+}
+
+// writeScript replaces path (a previous symlink or script) with an
+// executable script
+func writeScript(path, script string) error {
+	if _, err := os.Lstat(path); err == nil {
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("failed to remove existing %s: %v", path, err)
+		}
+	}
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+		return fmt.Errorf("failed to write %s: %v", path, err)
+	}
+	return nil
+}
+
+// writePHPShim writes the php wrapper script that runs a version's binary with
+// its own php.ini and conf.d directory
+func writePHPShim(shimPath, version string) error {
+	installDir, err := installedVersionDir(version)
+	if err != nil {
+		return err
+	}
+	env, err := versionEnv(version)
+	if err != nil {
+		return err
+	}
+	return writeScript(shimPath, wrapperScript(env, filepath.Join(installDir, "php")))
+}
+
+// writeComposerWrapper writes a script that runs a Composer phar with a PHP
+// version, its ini and, if enabled, its isolated COMPOSER_HOME
+func writeComposerWrapper(path, phpVersion, pharPath string) error {
+	installDir, err := installedVersionDir(phpVersion)
+	if err != nil {
+		return err
+	}
+	env, err := composerEnv(phpVersion)
+	if err != nil {
+		return err
+	}
+	return writeScript(path, wrapperScript(env, filepath.Join(installDir, "php"), pharPath))
+}
+
+// writeComposerShim writes a composer script that hands over to
+// 'phpvm composer-shim', which picks the PHP and Composer versions each time
+// it runs
+func writeComposerShim(path string) error {
+	self, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate the phpvm executable: %v", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(self); err == nil {
+		self = resolved
+	}
+	return writeScript(path, wrapperScript(nil, self, "composer-shim"))
+}
@@ -9,38 +9,75 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
-	"github.com/yourusername/phpvm/data"
 )
 
 var switchCmd = &cobra.Command{
 	Use:   "switch [version]",
 	Short: "Show or switch to a specific PHP version",
 	Long: `Show the current PHP version or switch to a specific version as active.
-If no version is specified, it shows the current active PHP version.`,
+If no version is specified, it shows the current active PHP version.
+
+A partial version such as 8.3 selects the newest installed 8.3 release.
+With --auto, the version is resolved from the project's .php-version file or
+composer.json (config.platform.php, then require.php), offering to install
+the best catalog match when no installed version satisfies it.
+
+Before switching, the version is checked against the nearest composer.json.
+Unmet requirements are reported as warnings, or abort the switch with --strict.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if switchAutoFlag {
+			return autoSwitch()
+		}
 		if len(args) == 0 {
 			return showCurrentVersion()
 		}
-		return setVersion(args[0])
+
+		version := args[0]
+		if resolution, err := matchInstalled(version, "the command line"); err == nil && resolution.Version != "" {
+			version = resolution.Version
+		}
+		return setVersion(version)
 	},
 }
 
+var (
+	switchStrictFlag bool
+	switchAutoFlag   bool
+)
+
 func init() {
+	switchCmd.Flags().BoolVar(&switchStrictFlag, "strict", false, "refuse to switch when composer.json requirements are not met")
+	switchCmd.Flags().BoolVar(&switchAutoFlag, "auto", false, "switch to the version requested by the current project")
 	RootCmd.AddCommand(switchCmd)
 }
 
 func showCurrentVersion() error {
-	out, err := exec.Command("php", "-v").CombinedOutput()
+	return showCurrent(false)
+}
+
+// autoSwitch switches to the version requested by the project in the
+// current directory
+func autoSwitch() error {
+	cwd, err := os.Getwd()
 	if err != nil {
-		return fmt.Errorf("failed to get PHP version: %v\n%s", err, out)
+		return fmt.Errorf("failed to get current directory: %v", err)
 	}
 
-	lines := strings.Split(string(out), "\n")
-	if len(lines) > 0 {
-		fmt.Println("Current PHP version:")
-		fmt.Println(lines[0])
+	resolution, err := resolveProjectVersion(cwd)
+	if err != nil {
+		return err
 	}
-	return nil
+	if resolution == nil {
+		return fmt.Errorf("no %s file or composer.json PHP requirement found", versionFile)
+	}
+
+	version, err := requireResolved(resolution, true)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Resolved PHP %s from %s\n", version, resolution.Source)
+	return setVersion(version)
 }
 
 func setVersion(version string) error {
@@ -50,6 +87,10 @@ func setVersion(version string) error {
 		return fmt.Errorf("failed to get home directory: %v", err)
 	}
 
+	if version == systemVersion {
+		return switchToSystem(filepath.Join(homeDir, ".phpvm", "bin"))
+	}
+
 	versionDir := filepath.Join(homeDir, ".phpvm", "versions", version)
 	phpBinary := filepath.Join(versionDir, "php")
 
@@ -57,25 +98,31 @@ func setVersion(version string) error {
 		return fmt.Errorf("PHP version %s is not installed. Use 'phpvm install %s' first", version, version)
 	}
 
+	warnIfEOL(version)
+
+	// Check the project's composer.json requirements
+	if err := checkProject(version, switchStrictFlag, false); err != nil {
+		return err
+	}
+
 	// Create symlink directory
 	binDir := filepath.Join(homeDir, ".phpvm", "bin")
 	if err := os.MkdirAll(binDir, 0755); err != nil {
 		return fmt.Errorf("failed to create bin directory: %v", err)
 	}
 
-	// Create or update symlink
-	symlinkPath := filepath.Join(binDir, "php")
+	// Make sure the version has its own php.ini and conf.d
+	if err := ensureINI(version, defaultINIPreset); err != nil {
+		return err
+	}
 
-	// Remove existing symlink if it exists
-	if _, err := os.Lstat(symlinkPath); err == nil {
-		if err := os.Remove(symlinkPath); err != nil {
-			return fmt.Errorf("failed to remove existing symlink: %v", err)
-		}
+	// Create or update the php wrapper that loads the version's ini
+	if err := writePHPShim(filepath.Join(binDir, "php"), version); err != nil {
+		return err
 	}
 
-	// Create new symlink
-	if err := os.Symlink(phpBinary, symlinkPath); err != nil {
-		return fmt.Errorf("failed to create symlink: %v", err)
+	if err := saveActiveVersion(version); err != nil {
+		return err
 	}
 
 	// Create or update Composer symlink
@@ -83,6 +130,11 @@ func setVersion(version string) error {
 		fmt.Printf("⚠️  Warning: Failed to create Composer symlink: %v\n", err)
 	}
 
+	// Follow the version's isolated COMPOSER_HOME, if enabled
+	if err := linkComposerHome(version); err != nil {
+		fmt.Printf("⚠️  Warning: %v\n", err)
+	}
+
 	fmt.Printf("✅ Switched to PHP %s\n", version)
 
 	// Automatically add to PATH for Linux
@@ -107,46 +159,39 @@ func setVersion(version string) error {
 	return nil
 }
 
-// createComposerSymlink creates a symlink for the Composer version compatible with the PHP version
+// createComposerSymlink creates the composer wrapper in the bin directory for
+// the Composer version compatible with the PHP version. In shim mode the
+// wrapper defers that choice to 'phpvm composer-shim' instead.
 func createComposerSymlink(phpVersion, binDir string) error {
-	// Find compatible Composer version
-	composerVersion := data.GetCompatibleComposerVersion(phpVersion)
-	if composerVersion == nil {
-		return fmt.Errorf("no compatible Composer version found for PHP %s", phpVersion)
-	}
+	// Create Composer wrapper in bin directory
+	composerSymlinkPath := filepath.Join(binDir, "composer")
 
-	// Get home directory
-	homeDir, err := os.UserHomeDir()
+	config, err := loadConfig()
 	if err != nil {
-		return fmt.Errorf("failed to get home directory: %v", err)
-	}
-
-	// Path to the Composer phar file
-	composerPharPath := filepath.Join(homeDir, ".phpvm", "composer", composerVersion.Version, "composer.phar")
-	
-	// Check if Composer phar exists
-	if _, err := os.Stat(composerPharPath); os.IsNotExist(err) {
-		return fmt.Errorf("Composer %s not found at %s", composerVersion.Version, composerPharPath)
+		return err
 	}
-
-	// Create Composer symlink in bin directory
-	composerSymlinkPath := filepath.Join(binDir, "composer")
-
-	// Remove existing symlink if it exists
-	if _, err := os.Lstat(composerSymlinkPath); err == nil {
-		if err := os.Remove(composerSymlinkPath); err != nil {
-			return fmt.Errorf("failed to remove existing Composer symlink: %v", err)
+	if config.ComposerShim {
+		if err := writeComposerShim(composerSymlinkPath); err != nil {
+			return fmt.Errorf("failed to create Composer shim: %v", err)
 		}
+		fmt.Printf("✅ Composer shim linked, versions are resolved when it runs\n")
+		return nil
 	}
 
-	// Get PHP binary path for the wrapper script
-	phpBinaryPath := filepath.Join(binDir, "php")
+	// Find the pinned or newest compatible Composer version
+	composerVersion, _, err := versionComposerVersion(phpVersion)
+	if err != nil {
+		return err
+	}
 
-	// Create wrapper script content
-	scriptContent := fmt.Sprintf("#!/bin/bash\n%s %s \"$@\"\n", phpBinaryPath, composerPharPath)
+	// Path to the Composer phar file, downloading it if it's missing
+	composerPharPath, err := ensureComposerPhar(composerVersion)
+	if err != nil {
+		return err
+	}
 
 	// Write the wrapper script
-	if err := os.WriteFile(composerSymlinkPath, []byte(scriptContent), 0755); err != nil {
+	if err := writeComposerWrapper(composerSymlinkPath, phpVersion, composerPharPath); err != nil {
 		return fmt.Errorf("failed to create Composer wrapper script: %v", err)
 	}
 
@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// systemVersion is the pseudo-version that steps phpvm aside so the PHP
+// provided by the OS (the next php on PATH) runs
+const systemVersion = "system"
+
+var systemCmd = &cobra.Command{
+	Use:   "system",
+	Short: "Switch back to the PHP provided by the system",
+	Long: `Make php and composer run the first binaries on PATH outside phpvm, such as
+/usr/bin/php. This is the same as 'phpvm switch system'; "system" can also be
+used in .php-version files, aliases and 'phpvm shell'.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setVersion(systemVersion)
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(systemCmd)
+}
+
+// systemShimDir returns the directory holding the php and composer shims
+// that hand over to the system binaries
+func systemShimDir() (string, error) {
+	root, err := phpvmHome()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(root, "system", "shims"), nil
+}
+
+// systemShimScript renders a script that runs the first tool on PATH that
+// isn't inside ~/.phpvm
+func systemShimScript(root, tool string) string {
+	return fmt.Sprintf(`#!/bin/sh
+# Generated by phpvm. Do not edit.
+# Runs the first %[2]s on PATH outside phpvm ("phpvm system").
+phpvm_root=%[1]s
+set -f
+IFS=:
+for dir in $PATH; do
+  case "$dir" in
+    "$phpvm_root"|"$phpvm_root"/*|'') continue ;;
+  esac
+  if [ -x "$dir/%[2]s" ] && [ ! -d "$dir/%[2]s" ]; then
+    exec "$dir/%[2]s" "$@"
+  fi
+done
+echo "phpvm: no system %[2]s found on PATH" >&2
+exit 127
+`, shellQuote(root), tool)
+}
+
+// writeSystemShim writes a shim for tool that runs the system binary
+func writeSystemShim(path, tool string) error {
+	root, err := phpvmHome()
+	if err != nil {
+		return err
+	}
+	return writeScript(path, systemShimScript(root, tool))
+}
+
+// ensureSystemShims writes the php and composer shims for the system
+// pseudo-version and returns their directory
+func ensureSystemShims() (string, error) {
+	dir, err := systemShimDir()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create %s: %v", dir, err)
+	}
+	for _, tool := range []string{"php", "composer"} {
+		if err := writeSystemShim(filepath.Join(dir, tool), tool); err != nil {
+			return "", err
+		}
+	}
+	return dir, nil
+}
+
+// systemBinary finds the first tool on PATH outside ~/.phpvm, the one the
+// system shims run
+func systemBinary(tool string) (string, error) {
+	root, err := phpvmHome()
+	if err != nil {
+		return "", err
+	}
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		if dir == "" || dir == root || strings.HasPrefix(dir, root+string(filepath.Separator)) {
+			continue
+		}
+		path := filepath.Join(dir, tool)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() && info.Mode()&0111 != 0 {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("no system %s found on PATH", tool)
+}
+
+// switchToSystem makes the global php and composer run the system binaries
+func switchToSystem(binDir string) error {
+	if err := os.MkdirAll(binDir, 0755); err != nil {
+		return fmt.Errorf("failed to create bin directory: %v", err)
+	}
+	for _, tool := range []string{"php", "composer"} {
+		if err := writeSystemShim(filepath.Join(binDir, tool), tool); err != nil {
+			return err
+		}
+	}
+	if err := saveActiveVersion(systemVersion); err != nil {
+		return err
+	}
+	if err := linkComposerHome(systemVersion); err != nil {
+		fmt.Printf("⚠️  Warning: %v\n", err)
+	}
+
+	if php, err := systemBinary("php"); err == nil {
+		fmt.Printf("✅ Switched to the system PHP (%s)\n", php)
+	} else {
+		fmt.Printf("✅ Switched to the system PHP\n")
+		fmt.Printf("⚠️  Warning: %v\n", err)
+	}
+	return nil
+}
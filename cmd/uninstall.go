@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var uninstallCmd = &cobra.Command{
+	Use:   "uninstall <version>",
+	Short: "Remove an installed PHP version",
+	Long: `Remove an installed PHP version together with its php.ini, extensions and
+Composer wrapper. The active version can't be removed; switch to another one
+first. Aliases pointing at the version are removed as well.
+
+Imported versions belong to the system or another package manager and can't
+be uninstalled; --unregister removes them from phpvm without touching the
+binary.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return uninstallVersion(args[0], uninstallUnregisterFlag)
+	},
+}
+
+var uninstallUnregisterFlag bool
+
+func init() {
+	uninstallCmd.Flags().BoolVar(&uninstallUnregisterFlag, "unregister", false, "remove an imported version from phpvm, leaving its binary alone")
+	RootCmd.AddCommand(uninstallCmd)
+}
+
+func uninstallVersion(version string, unregister bool) error {
+	if version == "" || version != filepath.Base(version) || strings.HasPrefix(version, ".") {
+		return fmt.Errorf("invalid PHP version %q", version)
+	}
+	dir, err := installedVersionDir(version)
+	if err != nil {
+		return err
+	}
+	if active, err := activeVersion(); err == nil && active == version {
+		return fmt.Errorf("PHP %s is the active version. Switch to another version first", version)
+	}
+
+	meta, err := loadMetadata(version)
+	if err != nil {
+		return err
+	}
+	if meta.Imported != nil && !unregister {
+		return fmt.Errorf("PHP %s was imported from %s, which phpvm doesn't manage. Use --unregister to remove it from phpvm only", version, meta.Imported.Binary)
+	}
+
+	aliases, err := loadAliases()
+	if err != nil {
+		return err
+	}
+	removed := false
+	for name, target := range aliases {
+		if target == version {
+			delete(aliases, name)
+			removed = true
+			fmt.Printf("Removed alias %s\n", name)
+		}
+	}
+	if removed {
+		if err := saveAliases(aliases); err != nil {
+			return err
+		}
+	}
+
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("failed to remove PHP %s: %v", version, err)
+	}
+	// The version directory only holds a symlink to an imported binary, so
+	// removing it leaves the binary in place
+	if meta.Imported != nil {
+		fmt.Printf("✅ PHP %s unregistered; %s was left in place\n", version, meta.Imported.Binary)
+		return nil
+	}
+	fmt.Printf("✅ PHP %s uninstalled\n", version)
+	return nil
+}
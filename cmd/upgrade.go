@@ -0,0 +1,189 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yourusername/phpvm/data"
+)
+
+var upgradeUninstallFlag bool
+
+var upgradeCmd = &cobra.Command{
+	Use:   "upgrade [branch...]",
+	Short: "Upgrade installed versions to the latest patch release",
+	Long: `Install the newest patch release of each outdated version (or only of the
+given branches, e.g. 'phpvm upgrade 8.3') and carry over its php.ini,
+conf.d, extensions and Composer pin. Aliases and the global default that
+pointed at the old patch are moved to the new one; .php-version files that
+name the old patch exactly are left alone. With --uninstall the old patch
+is removed afterwards.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return upgradeVersions(args, upgradeUninstallFlag)
+	},
+}
+
+func init() {
+	upgradeCmd.Flags().BoolVar(&upgradeUninstallFlag, "uninstall", false, "remove the old patch release after upgrading")
+	RootCmd.AddCommand(upgradeCmd)
+}
+
+func upgradeVersions(branches []string, uninstall bool) error {
+	upgrades, err := outdatedVersions()
+	if err != nil {
+		return err
+	}
+
+	var selected []PatchUpgrade
+	for _, upgrade := range upgrades {
+		if len(branches) == 0 {
+			selected = append(selected, upgrade)
+			continue
+		}
+		for _, branch := range branches {
+			ok, err := data.SatisfiesConstraint(upgrade.Installed, data.VersionRequestConstraint(branch))
+			if err != nil {
+				return fmt.Errorf("invalid branch %q: %v", branch, err)
+			}
+			if ok {
+				selected = append(selected, upgrade)
+				break
+			}
+		}
+	}
+	if len(selected) == 0 {
+		fmt.Println("✅ Nothing to upgrade")
+		return nil
+	}
+
+	failed := 0
+	for _, upgrade := range selected {
+		if err := upgradeVersion(upgrade, uninstall); err != nil {
+			fmt.Printf("❌ Failed to upgrade PHP %s: %v\n", upgrade.Installed, err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d upgrade(s) failed", failed)
+	}
+	return nil
+}
+
+// upgradeVersion installs the new patch release, carries the old version's
+// configuration over and moves aliases and the global default to it
+func upgradeVersion(upgrade PatchUpgrade, uninstall bool) error {
+	fmt.Printf("Upgrading PHP %s to %s...\n", upgrade.Installed, upgrade.Latest)
+
+	fresh := !isVersionInstalled(upgrade.Latest)
+	if err := installPHP(upgrade.Latest); err != nil {
+		return err
+	}
+	if fresh {
+		if err := carryOverVersion(upgrade.Installed, upgrade.Latest); err != nil {
+			return err
+		}
+	}
+
+	changed, err := retargetAliases(upgrade.Installed, upgrade.Latest)
+	if err != nil {
+		return err
+	}
+	for _, name := range changed {
+		fmt.Printf("✅ Alias %s -> %s\n", name, upgrade.Latest)
+	}
+
+	if active, err := activeVersion(); err == nil && active == upgrade.Installed {
+		if err := setVersion(upgrade.Latest); err != nil {
+			return err
+		}
+	}
+
+	if uninstall {
+		return uninstallVersion(upgrade.Installed, false)
+	}
+	fmt.Printf("✅ PHP %s upgraded to %s. Use 'phpvm uninstall %s' to remove the old release\n",
+		upgrade.Installed, upgrade.Latest, upgrade.Installed)
+	return nil
+}
+
+// carryOverVersion copies php.ini, conf.d and built extensions from one
+// patch release to another of the same branch, along with the extension
+// state and Composer pin in its metadata. Paths into the old version's
+// directory are rewritten.
+func carryOverVersion(from, to string) error {
+	fromDir, err := installedVersionDir(from)
+	if err != nil {
+		return err
+	}
+	toDir, err := installedVersionDir(to)
+	if err != nil {
+		return err
+	}
+	rewrite := func(s string) string {
+		return strings.ReplaceAll(s, fromDir+string(filepath.Separator), toDir+string(filepath.Separator))
+	}
+
+	// Patch releases share the extension API, so built extensions still load
+	extensions, _ := filepath.Glob(filepath.Join(fromDir, "ext", "*"))
+	if len(extensions) > 0 {
+		if err := os.MkdirAll(filepath.Join(toDir, "ext"), 0755); err != nil {
+			return fmt.Errorf("failed to create extension directory: %v", err)
+		}
+	}
+	for _, src := range extensions {
+		if err := copyFile(src, filepath.Join(toDir, "ext", filepath.Base(src)), 0755); err != nil {
+			return fmt.Errorf("failed to copy %s: %v", filepath.Base(src), err)
+		}
+	}
+
+	iniFiles, _ := filepath.Glob(filepath.Join(fromDir, "conf.d", "*.ini"))
+	scanned := len(iniFiles)
+	if _, err := os.Stat(filepath.Join(fromDir, "php.ini")); err == nil {
+		iniFiles = append(iniFiles, filepath.Join(fromDir, "php.ini"))
+	}
+	for _, src := range iniFiles {
+		content, err := os.ReadFile(src)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %v", src, err)
+		}
+		rel, _ := filepath.Rel(fromDir, src)
+		dst := filepath.Join(toDir, rel)
+		if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+			return fmt.Errorf("failed to create %s: %v", filepath.Dir(dst), err)
+		}
+		if err := os.WriteFile(dst, []byte(rewrite(string(content))), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %v", dst, err)
+		}
+	}
+
+	oldMeta, err := loadMetadata(from)
+	if err != nil {
+		return err
+	}
+	newMeta, err := loadMetadata(to)
+	if err != nil {
+		return err
+	}
+	for name, state := range oldMeta.Extensions {
+		if newMeta.Extensions == nil {
+			newMeta.Extensions = map[string]ExtensionState{}
+		}
+		state.Path = rewrite(state.Path)
+		newMeta.Extensions[name] = state
+	}
+	newMeta.Composer = oldMeta.Composer
+	newMeta.Modules = nil
+	if err := saveMetadata(newMeta); err != nil {
+		return err
+	}
+	fmt.Printf("✅ Carried over php.ini, %d conf.d file(s) and %d extension(s) from PHP %s\n",
+		scanned, len(extensions), from)
+
+	if newMeta.Composer != "" {
+		return relinkComposer(to)
+	}
+	return nil
+}
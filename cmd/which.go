@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+)
+
+var whichVersionFlag string
+
+var whichCmd = &cobra.Command{
+	Use:   "which <command>",
+	Short: "Print the file a command runs for the current directory",
+	Long: `Print the real file that php, composer, phpize, php-config, php-fpm or any
+other tool of the resolved PHP version runs, following phpvm's wrappers and
+symlinks. For composer this is the phar of the pinned or newest compatible
+Composer release.`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := whichCommand(args[0], whichVersionFlag)
+		if err != nil {
+			return err
+		}
+		fmt.Println(path)
+		return nil
+	},
+}
+
+var whereCmd = &cobra.Command{
+	Use:   "where [version]",
+	Short: "Print the install prefix of a PHP version",
+	Long: `Print the install prefix of a PHP version (the resolved one by default),
+for use in scripts. Partial versions and aliases are accepted.
+
+Prebuilt catalog versions hold just the php binary; only source builds and
+tarball installs have bin/ with phpize and php-config. To find a tool
+wherever it lives, use 'phpvm which', e.g. $(phpvm which php-config --version 8.3).`,
+	Args:         cobra.MaximumNArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		flagValue := ""
+		if len(args) == 1 {
+			flagValue = args[0]
+		}
+		version, err := targetVersion(flagValue)
+		if err != nil {
+			return err
+		}
+		dir, err := installedVersionDir(version)
+		if err != nil {
+			return err
+		}
+		fmt.Println(dir)
+		return nil
+	},
+}
+
+func init() {
+	whichCmd.Flags().StringVar(&whichVersionFlag, "version", "", "PHP version to look in (defaults to the resolved version)")
+	RootCmd.AddCommand(whichCmd, whereCmd)
+}
+
+// whichCommand returns the real file behind a command of a PHP version
+func whichCommand(name, versionFlag string) (string, error) {
+	version, _, err := targetResolution(versionFlag)
+	if err != nil {
+		return "", err
+	}
+	if version == systemVersion {
+		path, err := systemBinary(name)
+		if err != nil {
+			return "", err
+		}
+		return filepath.EvalSymlinks(path)
+	}
+	installDir, err := installedVersionDir(version)
+	if err != nil {
+		return "", err
+	}
+
+	var path string
+	switch name {
+	case "php":
+		path = filepath.Join(installDir, "php")
+	case "composer":
+		composerVersion, _, err := activeComposerVersion(version)
+		if err != nil {
+			return "", err
+		}
+		if path, err = composerPharPath(composerVersion.Version); err != nil {
+			return "", err
+		}
+		if _, err := os.Stat(path); err != nil {
+			return "", fmt.Errorf("Composer %s is not installed. Use 'phpvm composer install %s' first", composerVersion.Version, composerVersion.Version)
+		}
+	default:
+		if path, err = findBuildTool(installDir, name); err != nil {
+			return "", fmt.Errorf("%s is not available for PHP %s", name, version)
+		}
+	}
+
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %v", path, err)
+	}
+	return resolved, nil
+}
@@ -0,0 +1,222 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	xdebugVersionFlag string
+	pcovVersionFlag   string
+)
+
+var xdebugCmd = &cobra.Command{
+	Use:   "xdebug <on|off|mode=MODES>",
+	Short: "Toggle Xdebug for a PHP version",
+	Long: `Enable or disable Xdebug for the active PHP version (or --version).
+Passing mode=debug,coverage enables Xdebug with the given xdebug.mode.
+Xdebug is built from PECL the first time it is enabled.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		version, err := targetVersion(xdebugVersionFlag)
+		if err != nil {
+			return err
+		}
+
+		enable, settings, err := parseToggle(args[0], "xdebug")
+		if err != nil {
+			return err
+		}
+		return toggleExtension(version, "xdebug", enable, settings)
+	},
+}
+
+var pcovCmd = &cobra.Command{
+	Use:   "pcov <on|off>",
+	Short: "Toggle PCOV for a PHP version",
+	Long: `Enable or disable the PCOV code coverage driver for the active PHP
+version (or --version). PCOV is built from PECL the first time it is enabled.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		version, err := targetVersion(pcovVersionFlag)
+		if err != nil {
+			return err
+		}
+
+		enable, settings, err := parseToggle(args[0], "pcov")
+		if err != nil {
+			return err
+		}
+		if enable {
+			settings = append(settings, "pcov.enabled=1")
+		}
+		return toggleExtension(version, "pcov", enable, settings)
+	},
+}
+
+func init() {
+	xdebugCmd.Flags().StringVar(&xdebugVersionFlag, "version", "", "PHP version to operate on (defaults to the active version)")
+	pcovCmd.Flags().StringVar(&pcovVersionFlag, "version", "", "PHP version to operate on (defaults to the active version)")
+	RootCmd.AddCommand(xdebugCmd, pcovCmd)
+}
+
+// parseToggle interprets on/off/mode=... and returns the extra ini settings
+func parseToggle(arg, name string) (bool, []string, error) {
+	switch {
+	case arg == "on":
+		return true, nil, nil
+	case arg == "off":
+		return false, nil, nil
+	case name == "xdebug" && strings.HasPrefix(arg, "mode="):
+		return true, []string{"xdebug." + arg}, nil
+	}
+	return false, nil, fmt.Errorf("invalid argument %q (expected on or off)", arg)
+}
+
+// toggleExtension flips the dedicated conf.d file of a debug/coverage
+// extension, building the extension first if it isn't installed yet
+func toggleExtension(version, name string, enable bool, settings []string) error {
+	installDir, err := installedVersionDir(version)
+	if err != nil {
+		return err
+	}
+	if err := ensureINI(version, defaultINIPreset); err != nil {
+		return err
+	}
+
+	meta, err := loadMetadata(version)
+	if err != nil {
+		return err
+	}
+	if meta.Extensions == nil {
+		meta.Extensions = map[string]ExtensionState{}
+	}
+
+	state, installed := meta.Extensions[name]
+	if installed {
+		if _, err := os.Stat(state.Path); err != nil {
+			installed = false
+		}
+	}
+
+	if !installed {
+		if !enable {
+			fmt.Printf("ℹ️  %s is not installed for PHP %s\n", name, version)
+			return nil
+		}
+		fmt.Printf("%s is not installed for PHP %s, building it now...\n", name, version)
+		soPath, builtVersion, err := buildExtension(name, "", version, installDir)
+		if err != nil {
+			return err
+		}
+		state = ExtensionState{Version: builtVersion, Path: soPath, Zend: zendExtensions[name]}
+	}
+
+	confDir := filepath.Join(installDir, "conf.d")
+
+	// The toggle file replaces the one written by 'phpvm ext install'
+	os.Remove(filepath.Join(confDir, fmt.Sprintf("20-%s.ini", name)))
+
+	togglePath := filepath.Join(confDir, fmt.Sprintf("90-%s.ini", name))
+	if err := writeToggleINI(togglePath, name, state, enable, settings); err != nil {
+		return err
+	}
+
+	state.Enabled = enable
+	meta.Extensions[name] = state
+	meta.Modules = nil // the cached module scan is stale now
+	if err := saveMetadata(meta); err != nil {
+		return err
+	}
+
+	status := "disabled"
+	if enable {
+		status = "enabled"
+	}
+	fmt.Printf("✅ %s %s for PHP %s\n", name, status, version)
+	return printModuleState(version, name)
+}
+
+// writeToggleINI writes the conf.d file for a toggled extension. When
+// disabling, the previous settings are kept but commented out so that
+// re-enabling restores them.
+func writeToggleINI(path, name string, state ExtensionState, enable bool, settings []string) error {
+	directive := "extension"
+	if state.Zend {
+		directive = "zend_extension"
+	}
+
+	// Carry over settings from the existing file unless they are replaced
+	lines := []string{fmt.Sprintf("%s=%s", directive, state.Path)}
+	if content, err := os.ReadFile(path); err == nil {
+		for _, line := range strings.Split(string(content), "\n") {
+			line = strings.TrimLeft(strings.TrimSpace(line), "; ")
+			key, _, ok := strings.Cut(line, "=")
+			if !ok || key == directive || !strings.HasPrefix(key, name+".") || hasSetting(settings, key) {
+				continue
+			}
+			lines = append(lines, line)
+		}
+	}
+	lines = append(lines, settings...)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "; %s toggle, generated by phpvm. Use 'phpvm %s on|off' to change it.\n", name, name)
+	for _, line := range lines {
+		if !enable {
+			b.WriteString(";")
+		}
+		b.WriteString(line + "\n")
+	}
+
+	if err := os.WriteFile(path, []byte(b.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %v", path, err)
+	}
+	return nil
+}
+
+// hasSetting reports whether settings contains an assignment for key
+func hasSetting(settings []string, key string) bool {
+	for _, setting := range settings {
+		if k, _, _ := strings.Cut(setting, "="); k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// printModuleState shows whether the extension is loaded according to php -m
+func printModuleState(version, name string) error {
+	cmd, err := phpCommand(version, "-m")
+	if err != nil {
+		return err
+	}
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to run php -m: %v\n%s", err, output)
+	}
+
+	loaded := false
+	for _, line := range strings.Split(string(output), "\n") {
+		if strings.EqualFold(strings.TrimSpace(line), name) {
+			loaded = true
+			break
+		}
+	}
+
+	if loaded {
+		fmt.Printf("php -m: %s is loaded\n", name)
+		if name == "xdebug" {
+			if mode, _, err := getINIValue(version, "xdebug.mode"); err == nil {
+				fmt.Printf("xdebug.mode = %s\n", mode)
+			}
+		}
+	} else {
+		fmt.Printf("php -m: %s is not loaded\n", name)
+	}
+	return nil
+}
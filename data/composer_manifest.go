@@ -0,0 +1,80 @@
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ComposerVersionsURL is getcomposer.org's versions endpoint, which lists the
+// current release of every channel
+const ComposerVersionsURL = "https://getcomposer.org/versions"
+
+// composerManifestEntry is one release in the versions manifest
+type composerManifestEntry struct {
+	Path    string `json:"path"`
+	Version string `json:"version"`
+	MinPHP  int    `json:"min-php"`
+	SHA256  string `json:"sha256,omitempty"` // Not served by getcomposer.org; set in local manifests to pin phars
+}
+
+// manifestChannels maps manifest keys onto phpvm's channel names, in the
+// order they are read: a release listed under several keys gets the first
+// one's channel, so stable wins. "2" and "snapshot" are skipped: the former
+// repeats stable, the latter isn't a versioned release.
+var manifestChannels = []struct {
+	Key     string
+	Channel string
+}{
+	{"stable", ComposerChannelStable},
+	{"2.2", ComposerChannelLTS},
+	{"1", ComposerChannel1x},
+	{"preview", ComposerChannelPreview},
+}
+
+// ParseComposerManifest parses a versions manifest in the format served by
+// getcomposer.org/versions, e.g.
+//
+//	{"stable": [{"path": "/download/2.8.12/composer.phar", "version": "2.8.12", "min-php": 70205}]}
+func ParseComposerManifest(content []byte) ([]ComposerVersion, error) {
+	var manifest map[string][]composerManifestEntry
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse Composer versions manifest: %v", err)
+	}
+
+	var catalog []ComposerVersion
+	seen := map[string]bool{}
+	for _, channel := range manifestChannels {
+		for _, entry := range manifest[channel.Key] {
+			if entry.Version == "" || seen[entry.Version] {
+				continue
+			}
+			seen[entry.Version] = true
+
+			url := entry.Path
+			if strings.HasPrefix(url, "/") {
+				url = "https://getcomposer.org" + url
+			}
+			catalog = append(catalog, ComposerVersion{
+				Version:       entry.Version,
+				URL:           url,
+				Channel:       channel.Channel,
+				MinPHPVersion: phpVersionFromID(entry.MinPHP),
+				SHA256:        strings.ToLower(entry.SHA256),
+			})
+		}
+	}
+
+	if len(catalog) == 0 {
+		return nil, fmt.Errorf("Composer versions manifest lists no releases")
+	}
+	return catalog, nil
+}
+
+// phpVersionFromID turns a PHP_VERSION_ID such as 70205 into "7.2.5"
+func phpVersionFromID(id int) string {
+	if id <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d.%d.%d", id/10000, id/100%100, id%100)
+}
@@ -0,0 +1,84 @@
+package data
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseComposerManifest(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		want     []ComposerVersion
+		wantErr  bool
+	}{
+		{
+			name: "channels",
+			manifest: `{
+				"stable": [{"path": "/download/2.8.12/composer.phar", "version": "2.8.12", "min-php": 70205}],
+				"preview": [{"path": "/download/2.9.0-RC1/composer.phar", "version": "2.9.0-RC1", "min-php": 70205}],
+				"snapshot": [{"path": "/composer.phar", "version": "abc123", "min-php": 70205}],
+				"2.2": [{"path": "/download/2.2.25/composer.phar", "version": "2.2.25", "min-php": 50300}],
+				"1": [{"path": "/download/1.10.27/composer.phar", "version": "1.10.27", "min-php": 50300}]
+			}`,
+			want: []ComposerVersion{
+				{Version: "2.8.12", URL: "https://getcomposer.org/download/2.8.12/composer.phar", Channel: ComposerChannelStable, MinPHPVersion: "7.2.5"},
+				{Version: "2.2.25", URL: "https://getcomposer.org/download/2.2.25/composer.phar", Channel: ComposerChannelLTS, MinPHPVersion: "5.3.0"},
+				{Version: "1.10.27", URL: "https://getcomposer.org/download/1.10.27/composer.phar", Channel: ComposerChannel1x, MinPHPVersion: "5.3.0"},
+				{Version: "2.9.0-RC1", URL: "https://getcomposer.org/download/2.9.0-RC1/composer.phar", Channel: ComposerChannelPreview, MinPHPVersion: "7.2.5"},
+			},
+		},
+		{
+			// Between releases, preview repeats stable; the release must stay stable
+			name: "stable wins over preview",
+			manifest: `{
+				"preview": [{"path": "/download/2.8.12/composer.phar", "version": "2.8.12", "min-php": 70205}],
+				"stable": [{"path": "/download/2.8.12/composer.phar", "version": "2.8.12", "min-php": 70205}],
+				"2": [{"path": "/download/2.8.12/composer.phar", "version": "2.8.12", "min-php": 70205}]
+			}`,
+			want: []ComposerVersion{
+				{Version: "2.8.12", URL: "https://getcomposer.org/download/2.8.12/composer.phar", Channel: ComposerChannelStable, MinPHPVersion: "7.2.5"},
+			},
+		},
+		{
+			name:     "pinned checksum and absolute URL",
+			manifest: `{"stable": [{"path": "https://mirror.example.com/composer.phar", "version": "2.8.12", "sha256": "ABCDEF"}]}`,
+			want: []ComposerVersion{
+				{Version: "2.8.12", URL: "https://mirror.example.com/composer.phar", Channel: ComposerChannelStable, SHA256: "abcdef"},
+			},
+		},
+		{
+			name:     "entries without a version",
+			manifest: `{"stable": [{"path": "/composer.phar"}]}`,
+			wantErr:  true,
+		},
+		{
+			name:     "no known channels",
+			manifest: `{"snapshot": [{"path": "/composer.phar", "version": "abc123"}]}`,
+			wantErr:  true,
+		},
+		{
+			name:     "invalid JSON",
+			manifest: `{"stable": `,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseComposerManifest([]byte(tt.manifest))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseComposerManifest() = %v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseComposerManifest() failed: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseComposerManifest() =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
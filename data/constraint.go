@@ -0,0 +1,187 @@
+package data
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// devBound turns a stable constraint bound into the lowest build of that
+// release, so that ">=8.4" admits 8.4.0RC1 and "<8.5" excludes 8.5.0RC1, as
+// in Composer
+func devBound(v Version) Version {
+	if !v.IsPreRelease() {
+		v.Stability = StabilityDev
+		v.Pre = 0
+	}
+	return v
+}
+
+// nextBranch returns the dev build of the release after v at the given
+// number of parts: nextBranch(8.1.2, 2) is 8.2.0-dev
+func nextBranch(v Version, parts int) Version {
+	switch parts {
+	case 1:
+		return Version{Major: v.Major + 1, Stability: StabilityDev}
+	case 2:
+		return Version{Major: v.Major, Minor: v.Minor + 1, Stability: StabilityDev}
+	}
+	return Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch + 1, Stability: StabilityDev}
+}
+
+// operatorSpacing matches whitespace between a comparison operator and its
+// version, e.g. ">= 8.1"
+var operatorSpacing = regexp.MustCompile(`(>=|<=|!=|==|<>|>|<|=|\^|~)\s+`)
+
+// SatisfiesConstraint reports whether a PHP version matches a Composer
+// version constraint such as "^8.1", ">=7.4 <8.3" or "~8.2.0 || ^8.3"
+func SatisfiesConstraint(version, constraint string) (bool, error) {
+	parsed, err := ParseVersion(version)
+	if err != nil {
+		return false, err
+	}
+
+	constraint = strings.ReplaceAll(constraint, "||", "|")
+	for _, alternative := range strings.Split(constraint, "|") {
+		ok, err := satisfiesAll(parsed, strings.TrimSpace(alternative))
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// satisfiesAll checks a conjunction of constraints separated by commas or
+// whitespace, including hyphenated ranges like "8.0 - 8.2"
+func satisfiesAll(version Version, constraint string) (bool, error) {
+	if from, to, found := strings.Cut(constraint, " - "); found {
+		lower, err := satisfiesSingle(version, ">="+strings.TrimSpace(from))
+		if err != nil || !lower {
+			return false, err
+		}
+		upper, err := ParseVersion(to)
+		if err != nil {
+			return false, err
+		}
+		if upper.Parts() == 3 {
+			return version.Compare(upper) <= 0, nil
+		}
+		// A partial upper bound includes the whole branch: "8.0 - 8.2" allows 8.2.x
+		return version.Compare(nextBranch(upper, upper.Parts())) < 0, nil
+	}
+
+	constraint = operatorSpacing.ReplaceAllString(constraint, "$1")
+	terms := strings.FieldsFunc(constraint, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	})
+	if len(terms) == 0 {
+		return false, fmt.Errorf("empty version constraint")
+	}
+
+	for _, term := range terms {
+		ok, err := satisfiesSingle(version, term)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// satisfiesSingle checks one constraint term
+func satisfiesSingle(version Version, term string) (bool, error) {
+	// Stability flags don't affect which PHP release matches
+	term, _, _ = strings.Cut(term, "@")
+	if term == "*" || term == "" {
+		return true, nil
+	}
+
+	for _, op := range []string{">=", "<=", "!=", "<>", "==", ">", "<", "=", "^", "~"} {
+		if !strings.HasPrefix(term, op) {
+			continue
+		}
+		bound, err := ParseVersion(strings.TrimPrefix(term, op))
+		if err != nil {
+			return false, err
+		}
+		cmp := version.Compare(bound)
+		switch op {
+		case ">=":
+			return version.Compare(devBound(bound)) >= 0, nil
+		case "<=":
+			return cmp <= 0, nil
+		case ">":
+			return cmp > 0, nil
+		case "<":
+			return version.Compare(devBound(bound)) < 0, nil
+		case "!=", "<>":
+			return cmp != 0, nil
+		case "==", "=":
+			return cmp == 0, nil
+		case "^":
+			// ^8.1 allows >=8.1.0 <9.0.0, ^0.3 allows >=0.3.0 <0.4.0
+			upper := nextBranch(bound, 1)
+			if bound.Major == 0 {
+				upper = nextBranch(bound, 2)
+			}
+			return version.Compare(devBound(bound)) >= 0 && version.Compare(upper) < 0, nil
+		case "~":
+			// ~8.1 allows >=8.1 <9.0, ~8.1.2 allows >=8.1.2 <8.2.0
+			upper := nextBranch(bound, 1)
+			if bound.Parts() == 3 {
+				upper = nextBranch(bound, 2)
+			}
+			return version.Compare(devBound(bound)) >= 0 && version.Compare(upper) < 0, nil
+		}
+	}
+
+	// Wildcards: 8.* or 8.1.*
+	if strings.HasSuffix(term, ".*") {
+		bound, err := ParseVersion(strings.TrimSuffix(term, ".*"))
+		if err != nil {
+			return false, err
+		}
+		upper := nextBranch(bound, bound.Parts())
+		return version.Compare(devBound(bound)) >= 0 && version.Compare(upper) < 0, nil
+	}
+
+	// A bare version is an exact match
+	bound, err := ParseVersion(term)
+	if err != nil {
+		return false, err
+	}
+	return version.Compare(bound) == 0, nil
+}
+
+// NewestSatisfying returns the newest of versions that matches constraint,
+// or an empty string when none does
+func NewestSatisfying(versions []string, constraint string) (string, error) {
+	best := ""
+	for _, version := range versions {
+		// Skip labels that aren't versions, like "nightly"
+		if _, err := ParseVersion(version); err != nil {
+			continue
+		}
+		ok, err := SatisfiesConstraint(version, constraint)
+		if err != nil {
+			return "", err
+		}
+		if ok && (best == "" || CompareVersions(version, best) > 0) {
+			best = version
+		}
+	}
+	return best, nil
+}
+
+// VersionRequestConstraint turns a version request such as "8.3" or
+// "8.3.12" into a constraint. Partial versions match the newest patch of
+// their branch; anything else is used as a Composer constraint.
+func VersionRequestConstraint(request string) string {
+	request = strings.TrimSpace(request)
+	if v, err := ParseVersion(request); err == nil && v.Parts() < 3 && !v.IsPreRelease() && !strings.ContainsAny(request, "^~<>=*|, ") {
+		return request + ".*"
+	}
+	return request
+}
@@ -0,0 +1,122 @@
+package data
+
+import "testing"
+
+func TestSatisfiesConstraint(t *testing.T) {
+	tests := []struct {
+		version    string
+		constraint string
+		want       bool
+		wantErr    bool
+	}{
+		{version: "8.3.12", constraint: "*", want: true},
+		{version: "8.3.12", constraint: "8.3.12", want: true},
+		{version: "8.3.11", constraint: "8.3.12", want: false},
+		{version: "8.3.12", constraint: "=8.3.12", want: true},
+		{version: "8.3.12", constraint: "!=8.3.12", want: false},
+		{version: "8.3.12", constraint: ">=8.1", want: true},
+		{version: "8.0.30", constraint: ">= 8.1", want: false},
+		{version: "8.4.0RC1", constraint: ">=8.4", want: true},
+		{version: "8.5.0RC1", constraint: "<8.5", want: false},
+		{version: "8.4.15", constraint: "<8.5", want: true},
+		{version: "8.3.0", constraint: ">8.3.0", want: false},
+		{version: "8.3.0", constraint: "<=8.3.0", want: true},
+		{version: "8.4.1", constraint: "^8.1", want: true},
+		{version: "9.0.0", constraint: "^8.1", want: false},
+		{version: "8.0.30", constraint: "^8.1", want: false},
+		{version: "0.3.5", constraint: "^0.3", want: true},
+		{version: "0.4.0", constraint: "^0.3", want: false},
+		{version: "8.4.0", constraint: "~8.1", want: true},
+		{version: "8.1.5", constraint: "~8.1.2", want: true},
+		{version: "8.2.0", constraint: "~8.1.2", want: false},
+		{version: "8.3.12", constraint: "8.3.*", want: true},
+		{version: "8.4.0", constraint: "8.3.*", want: false},
+		{version: "8.4.0", constraint: "8.*", want: true},
+		{version: "7.4.33", constraint: ">=7.4 <8.3", want: true},
+		{version: "8.3.0", constraint: ">=7.4 <8.3", want: false},
+		{version: "7.4.33", constraint: ">=7.4,<8.3", want: true},
+		{version: "8.3.4", constraint: "~8.2.0 || ^8.3", want: true},
+		{version: "8.2.9", constraint: "~8.2.0 || ^8.3", want: true},
+		{version: "8.1.0", constraint: "~8.2.0 || ^8.3", want: false},
+		{version: "8.2.0", constraint: "^7.4|^8.0", want: true},
+		{version: "8.2.9", constraint: "8.0 - 8.2", want: true},
+		{version: "8.3.0", constraint: "8.0 - 8.2", want: false},
+		{version: "8.2.1", constraint: "8.0 - 8.2.0", want: false},
+		{version: "8.3.0", constraint: ">=8.1@dev", want: true},
+		{version: "8.3.0", constraint: "", wantErr: true},
+		{version: "8.3.0", constraint: ">=banana", wantErr: true},
+		{version: "nightly", constraint: "*", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := SatisfiesConstraint(tt.version, tt.constraint)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("SatisfiesConstraint(%q, %q) = %v, want an error", tt.version, tt.constraint, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("SatisfiesConstraint(%q, %q) failed: %v", tt.version, tt.constraint, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("SatisfiesConstraint(%q, %q) = %v, want %v", tt.version, tt.constraint, got, tt.want)
+		}
+	}
+}
+
+func TestVersionRequestConstraint(t *testing.T) {
+	tests := []struct {
+		request string
+		want    string
+	}{
+		{"8.3", "8.3.*"},
+		{"8", "8.*"},
+		{" 8.3 ", "8.3.*"},
+		{"8.3.12", "8.3.12"},
+		{"8.5.0RC2", "8.5.0RC2"},
+		{"^8.1", "^8.1"},
+		{">=8.1 <8.4", ">=8.1 <8.4"},
+	}
+
+	for _, tt := range tests {
+		if got := VersionRequestConstraint(tt.request); got != tt.want {
+			t.Errorf("VersionRequestConstraint(%q) = %q, want %q", tt.request, got, tt.want)
+		}
+	}
+}
+
+func TestNewestSatisfying(t *testing.T) {
+	installed := []string{"7.4.33", "8.2.20", "8.3.9", "8.3.12", "8.4.0RC3", "nightly", "8.2-remi"}
+	tests := []struct {
+		constraint string
+		want       string
+		wantErr    bool
+	}{
+		{constraint: "8.3.*", want: "8.3.12"},
+		{constraint: "^8.1", want: "8.4.0RC3"},
+		{constraint: "<8.4", want: "8.3.12"},
+		{constraint: "7.4.*", want: "7.4.33"},
+		{constraint: "8.3.9", want: "8.3.9"},
+		{constraint: "^9.0", want: ""},
+		{constraint: ">=oops", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := NewestSatisfying(installed, tt.constraint)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("NewestSatisfying(%q) = %q, want an error", tt.constraint, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("NewestSatisfying(%q) failed: %v", tt.constraint, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("NewestSatisfying(%q) = %q, want %q", tt.constraint, got, tt.want)
+		}
+	}
+}
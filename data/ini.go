@@ -0,0 +1,57 @@
+package data
+
+// INIPreset is a php.ini template used when phpvm creates a version's ini
+type INIPreset struct {
+	Name     string
+	Settings [][2]string
+}
+
+// INIPresets contains the php.ini templates phpvm can generate
+var INIPresets = map[string]INIPreset{
+	"development": {
+		Name: "development",
+		Settings: [][2]string{
+			{"memory_limit", "512M"},
+			{"max_execution_time", "0"},
+			{"error_reporting", "E_ALL"},
+			{"display_errors", "On"},
+			{"display_startup_errors", "On"},
+			{"log_errors", "On"},
+			{"zend.assertions", "1"},
+			{"post_max_size", "64M"},
+			{"upload_max_filesize", "64M"},
+			{"date.timezone", "UTC"},
+			{"opcache.enable_cli", "0"},
+		},
+	},
+	"production": {
+		Name: "production",
+		Settings: [][2]string{
+			{"memory_limit", "256M"},
+			{"max_execution_time", "30"},
+			{"error_reporting", "E_ALL & ~E_DEPRECATED & ~E_STRICT"},
+			{"display_errors", "Off"},
+			{"display_startup_errors", "Off"},
+			{"log_errors", "On"},
+			{"zend.assertions", "-1"},
+			{"post_max_size", "8M"},
+			{"upload_max_filesize", "2M"},
+			{"date.timezone", "UTC"},
+			{"expose_php", "Off"},
+			{"opcache.enable_cli", "1"},
+		},
+	},
+}
+
+// GetINIPreset looks up a preset by name, accepting "dev" and "prod" as
+// shorthands
+func GetINIPreset(name string) (INIPreset, bool) {
+	switch name {
+	case "dev":
+		name = "development"
+	case "prod":
+		name = "production"
+	}
+	preset, ok := INIPresets[name]
+	return preset, ok
+}
@@ -0,0 +1,79 @@
+package data
+
+import (
+	"time"
+)
+
+// PHPBranch holds the support dates of a PHP minor branch
+type PHPBranch struct {
+	Branch               string
+	ActiveSupportUntil   time.Time // End of bug fixes
+	SecuritySupportUntil time.Time // End of security fixes (end of life)
+}
+
+// SupportStatus is where a branch is in its support lifecycle
+type SupportStatus string
+
+// Support statuses, as published on php.net/supported-versions
+const (
+	SupportActive   SupportStatus = "active"
+	SupportSecurity SupportStatus = "security"
+	SupportEOL      SupportStatus = "eol"
+	SupportUnknown  SupportStatus = "unknown"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// Branches lists the support dates of PHP branches, from php.net
+var Branches = []PHPBranch{
+	{Branch: "5.6", ActiveSupportUntil: date(2017, 1, 19), SecuritySupportUntil: date(2018, 12, 31)},
+	{Branch: "7.0", ActiveSupportUntil: date(2017, 12, 3), SecuritySupportUntil: date(2019, 1, 10)},
+	{Branch: "7.1", ActiveSupportUntil: date(2018, 12, 1), SecuritySupportUntil: date(2019, 12, 1)},
+	{Branch: "7.2", ActiveSupportUntil: date(2019, 11, 30), SecuritySupportUntil: date(2020, 11, 30)},
+	{Branch: "7.3", ActiveSupportUntil: date(2020, 12, 6), SecuritySupportUntil: date(2021, 12, 6)},
+	{Branch: "7.4", ActiveSupportUntil: date(2021, 11, 28), SecuritySupportUntil: date(2022, 11, 28)},
+	{Branch: "8.0", ActiveSupportUntil: date(2022, 11, 26), SecuritySupportUntil: date(2023, 11, 26)},
+	{Branch: "8.1", ActiveSupportUntil: date(2023, 11, 25), SecuritySupportUntil: date(2025, 12, 31)},
+	{Branch: "8.2", ActiveSupportUntil: date(2024, 12, 31), SecuritySupportUntil: date(2026, 12, 31)},
+	{Branch: "8.3", ActiveSupportUntil: date(2025, 12, 31), SecuritySupportUntil: date(2027, 12, 31)},
+	{Branch: "8.4", ActiveSupportUntil: date(2026, 12, 31), SecuritySupportUntil: date(2028, 12, 31)},
+	{Branch: "8.5", ActiveSupportUntil: date(2027, 12, 31), SecuritySupportUntil: date(2029, 12, 31)},
+}
+
+// FindBranch returns the support dates of the branch a version belongs to,
+// or nil for unknown branches and labels like "nightly"
+func FindBranch(version string) *PHPBranch {
+	v, err := ParseVersion(version)
+	if err != nil {
+		return nil
+	}
+	for i := range Branches {
+		if Branches[i].Branch == v.Branch() {
+			return &Branches[i]
+		}
+	}
+	return nil
+}
+
+// Status returns the branch's support status at a point in time. Support
+// lasts until the end of the listed day.
+func (b *PHPBranch) Status(at time.Time) SupportStatus {
+	switch {
+	case at.Before(b.ActiveSupportUntil.AddDate(0, 0, 1)):
+		return SupportActive
+	case at.Before(b.SecuritySupportUntil.AddDate(0, 0, 1)):
+		return SupportSecurity
+	}
+	return SupportEOL
+}
+
+// VersionSupport returns the support status of a PHP version today
+func VersionSupport(version string) SupportStatus {
+	branch := FindBranch(version)
+	if branch == nil {
+		return SupportUnknown
+	}
+	return branch.Status(time.Now())
+}
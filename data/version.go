@@ -0,0 +1,158 @@
+package data
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Stability is the release stage of a version, ordered from least to most
+// stable
+type Stability int
+
+// Release stages, in the order PHP and Composer rank them
+const (
+	StabilityDev Stability = iota
+	StabilityAlpha
+	StabilityBeta
+	StabilityRC
+	StabilityStable
+)
+
+// String returns the suffix PHP uses for a stability, e.g. "RC"
+func (s Stability) String() string {
+	switch s {
+	case StabilityDev:
+		return "-dev"
+	case StabilityAlpha:
+		return "alpha"
+	case StabilityBeta:
+		return "beta"
+	case StabilityRC:
+		return "RC"
+	}
+	return ""
+}
+
+// Version is a parsed PHP (or Composer) version such as "8.3.12", "8.4.0RC3"
+// or "2.9.0-beta1"
+type Version struct {
+	Major, Minor, Patch int
+	Stability           Stability
+	Pre                 int // Number of the pre-release, e.g. 3 for RC3
+	parts               int // How many numeric parts were given
+}
+
+// versionPattern matches "8", "8.3", "v8.3.12", "8.4.0RC3", "2.9.0-beta1" and
+// "8.4.0-dev". Anything after a trailing "-", "+" or "~" that isn't a
+// stability (distribution builds like "8.1.2-1ubuntu2.14") is ignored.
+var versionPattern = regexp.MustCompile(`(?i)^v?(\d+)(?:\.(\d+))?(?:\.(\d+))?(?:[-_.]?(dev|alpha|a|beta|b|rc)\.?(\d*))?([-+~].*)?$`)
+
+// ParseVersion parses a version string
+func ParseVersion(version string) (Version, error) {
+	match := versionPattern.FindStringSubmatch(strings.TrimSpace(version))
+	if match == nil {
+		return Version{}, fmt.Errorf("invalid version %q", version)
+	}
+
+	v := Version{Stability: StabilityStable}
+	for i, field := range []*int{&v.Major, &v.Minor, &v.Patch} {
+		if match[i+1] == "" {
+			break
+		}
+		n, err := strconv.Atoi(match[i+1])
+		if err != nil {
+			return Version{}, fmt.Errorf("invalid version %q", version)
+		}
+		*field = n
+		v.parts++
+	}
+
+	switch strings.ToLower(match[4]) {
+	case "dev":
+		v.Stability = StabilityDev
+	case "alpha", "a":
+		v.Stability = StabilityAlpha
+	case "beta", "b":
+		v.Stability = StabilityBeta
+	case "rc":
+		v.Stability = StabilityRC
+	}
+	if match[5] != "" {
+		v.Pre, _ = strconv.Atoi(match[5])
+	}
+	return v, nil
+}
+
+// Parts returns how many numeric parts the parsed string had: 2 for "8.3"
+func (v Version) Parts() int {
+	return v.parts
+}
+
+// IsPreRelease reports whether the version is a dev, alpha, beta or RC build
+func (v Version) IsPreRelease() bool {
+	return v.Stability != StabilityStable
+}
+
+// Branch returns the major.minor branch, e.g. "8.3"
+func (v Version) Branch() string {
+	return fmt.Sprintf("%d.%d", v.Major, v.Minor)
+}
+
+// String formats the version the way PHP does, e.g. "8.4.0RC3"
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Stability != StabilityStable {
+		s += v.Stability.String()
+		if v.Pre > 0 {
+			s += strconv.Itoa(v.Pre)
+		}
+	}
+	return s
+}
+
+// Compare returns -1, 0 or 1 as v is older than, equal to or newer than
+// other. Pre-releases sort before the release they lead up to:
+// 8.4.0-dev < 8.4.0alpha1 < 8.4.0beta2 < 8.4.0RC3 < 8.4.0.
+func (v Version) Compare(other Version) int {
+	for _, pair := range [][2]int{
+		{v.Major, other.Major},
+		{v.Minor, other.Minor},
+		{v.Patch, other.Patch},
+		{int(v.Stability), int(other.Stability)},
+		{v.Pre, other.Pre},
+	} {
+		if pair[0] < pair[1] {
+			return -1
+		}
+		if pair[0] > pair[1] {
+			return 1
+		}
+	}
+	return 0
+}
+
+// CompareVersions compares two version strings, returning -1, 0 or 1.
+// Strings that aren't versions, like "nightly", sort after all versions.
+func CompareVersions(a, b string) int {
+	va, errA := ParseVersion(a)
+	vb, errB := ParseVersion(b)
+	switch {
+	case errA != nil && errB != nil:
+		return strings.Compare(a, b)
+	case errA != nil:
+		return 1
+	case errB != nil:
+		return -1
+	}
+	return va.Compare(vb)
+}
+
+// SortVersions sorts version strings from oldest to newest
+func SortVersions(versions []string) {
+	sort.SliceStable(versions, func(i, j int) bool {
+		return CompareVersions(versions[i], versions[j]) < 0
+	})
+}
@@ -0,0 +1,95 @@
+package data
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		parts   int
+		pre     bool
+		wantErr bool
+	}{
+		{input: "8.3.12", want: "8.3.12", parts: 3},
+		{input: "8.3", want: "8.3.0", parts: 2},
+		{input: "8", want: "8.0.0", parts: 1},
+		{input: "v8.3.12", want: "8.3.12", parts: 3},
+		{input: " 8.3.12 ", want: "8.3.12", parts: 3},
+		{input: "8.4.0RC3", want: "8.4.0RC3", parts: 3, pre: true},
+		{input: "8.4.0rc3", want: "8.4.0RC3", parts: 3, pre: true},
+		{input: "8.4.0alpha1", want: "8.4.0alpha1", parts: 3, pre: true},
+		{input: "8.4.0beta2", want: "8.4.0beta2", parts: 3, pre: true},
+		{input: "2.9.0-beta1", want: "2.9.0beta1", parts: 3, pre: true},
+		{input: "8.4.0-dev", want: "8.4.0-dev", parts: 3, pre: true},
+		{input: "8.1.2-1ubuntu2.14", want: "8.1.2", parts: 3},
+		{input: "8.1.2+deb12", want: "8.1.2", parts: 3},
+		{input: "nightly", wantErr: true},
+		{input: "", wantErr: true},
+		{input: "8.x", wantErr: true},
+		{input: "../8.3", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			v, err := ParseVersion(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseVersion(%q) = %v, want an error", tt.input, v)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseVersion(%q) failed: %v", tt.input, err)
+			}
+			if got := v.String(); got != tt.want {
+				t.Errorf("ParseVersion(%q) = %s, want %s", tt.input, got, tt.want)
+			}
+			if got := v.Parts(); got != tt.parts {
+				t.Errorf("ParseVersion(%q).Parts() = %d, want %d", tt.input, got, tt.parts)
+			}
+			if got := v.IsPreRelease(); got != tt.pre {
+				t.Errorf("ParseVersion(%q).IsPreRelease() = %v, want %v", tt.input, got, tt.pre)
+			}
+		})
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"8.3.12", "8.3.12", 0},
+		{"8.3", "8.3.0", 0},
+		{"8.3.9", "8.3.12", -1},
+		{"8.10.0", "8.9.0", 1},
+		{"7.4.33", "8.0.0", -1},
+		{"8.4.0RC3", "8.4.0", -1},
+		{"8.4.0-dev", "8.4.0alpha1", -1},
+		{"8.4.0alpha1", "8.4.0beta1", -1},
+		{"8.4.0beta2", "8.4.0RC1", -1},
+		{"8.4.0RC1", "8.4.0RC3", -1},
+		{"8.4.0RC3", "8.3.99", 1},
+		{"nightly", "8.4.0", 1},
+		{"8.4.0", "nightly", -1},
+		{"nightly", "nightly", 0},
+	}
+
+	for _, tt := range tests {
+		if got := CompareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("CompareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestSortVersions(t *testing.T) {
+	versions := []string{"nightly", "8.4.0", "8.3.12", "8.4.0RC3", "7.4.33", "8.3.9"}
+	SortVersions(versions)
+	want := []string{"7.4.33", "8.3.9", "8.3.12", "8.4.0RC3", "8.4.0", "nightly"}
+	if !reflect.DeepEqual(versions, want) {
+		t.Errorf("SortVersions() = %v, want %v", versions, want)
+	}
+}
@@ -1,7 +1,6 @@
 package data
 
 import (
-	"strings"
 	"time"
 )
 
@@ -11,17 +10,47 @@ type PHPVersion struct {
 	Released       time.Time
 	BinaryURLx64   string
 	BinaryURLarm64 string
+	SourceURL      string // php-src tarball, built locally when there is no binary
 }
 
+// NightlyVersion is the catalog label for builds of php-src master
+const NightlyVersion = "nightly"
+
+// IsPreRelease reports whether the version is an alpha, beta, RC or nightly
+// build, which 'phpvm list' hides unless asked for
+func (p *PHPVersion) IsPreRelease() bool {
+	v, err := ParseVersion(p.Version)
+	return err != nil || v.IsPreRelease()
+}
+
+// IsSourceBuild reports whether the version is compiled from source rather
+// than downloaded as a binary
+func (p *PHPVersion) IsSourceBuild() bool {
+	return p.BinaryURLx64 == "" && p.BinaryURLarm64 == "" && p.SourceURL != ""
+}
+
+// ComposerVersion represents a Composer release and the PHP versions it runs on
 type ComposerVersion struct {
-	Version        string
-	Released       time.Time
-	URL            string
-	MinPHPVersion  string
-	MaxPHPVersion  string
-	CompatiblePHP  []string // Specific PHP versions tested/confirmed compatible
+	Version       string
+	Released      time.Time
+	URL           string
+	Channel       string // stable, preview, lts (2.2 LTS) or 1.x
+	MinPHPVersion string // Lowest supported PHP version
+	MaxPHPVersion string // Highest supported PHP version, empty when unbounded
+	// Pinned SHA-256 of the phar. The built-in catalog and getcomposer.org's
+	// manifest don't carry one, so it is fetched from getcomposer.org when
+	// empty; a local PHPVM_COMPOSER_MANIFEST can pin it with a "sha256" key.
+	SHA256 string
 }
 
+// Composer release channels
+const (
+	ComposerChannelStable  = "stable"
+	ComposerChannelPreview = "preview"
+	ComposerChannelLTS     = "lts"
+	ComposerChannel1x      = "1.x"
+)
+
 // AvailableVersions contains all available PHP versions
 var AvailableVersions = []PHPVersion{
 	{
@@ -30,56 +59,117 @@ var AvailableVersions = []PHPVersion{
 		BinaryURLx64:   "https://download.herdphp.com/herd-lite/linux/x64/8.4/php",
 		BinaryURLarm64: "https://download.herdphp.com/herd-lite/linux/arm64/8.4/php",
 	},
+	{
+		Version:   "8.5.0RC2",
+		Released:  time.Date(2025, 10, 9, 0, 0, 0, 0, time.UTC),
+		SourceURL: "https://github.com/php/php-src/archive/refs/tags/php-8.5.0RC2.tar.gz",
+	},
+	{
+		Version:   NightlyVersion,
+		SourceURL: "https://github.com/php/php-src/archive/refs/heads/master.tar.gz",
+	},
+}
+
+// LatestPatch returns the newest stable catalog release in the same branch
+// as version, or nil when the branch isn't in the catalog
+func LatestPatch(version string) *PHPVersion {
+	v, err := ParseVersion(version)
+	if err != nil {
+		return nil
+	}
+
+	var latest *PHPVersion
+	for i := range AvailableVersions {
+		candidate := &AvailableVersions[i]
+		parsed, err := ParseVersion(candidate.Version)
+		if err != nil || parsed.IsPreRelease() || parsed.Branch() != v.Branch() {
+			continue
+		}
+		if latest == nil || CompareVersions(candidate.Version, latest.Version) > 0 {
+			latest = candidate
+		}
+	}
+	return latest
 }
 
+// FindPHPVersion returns the catalog entry for a PHP version, or nil
+func FindPHPVersion(version string) *PHPVersion {
+	for i := range AvailableVersions {
+		if AvailableVersions[i].Version == version {
+			return &AvailableVersions[i]
+		}
+	}
+	return nil
+}
+
+// AvailableComposerVersions is the built-in Composer catalog, used when the
+// versions manifest from getcomposer.org hasn't been fetched. It pins no
+// checksums, so new downloads are verified against the SHA-256 published
+// next to them.
 var AvailableComposerVersions = []ComposerVersion{
 	{
-		Version:        "2.8.11",
-		Released:       time.Date(2024, 8, 21, 0, 0, 0, 0, time.UTC),
-		URL:            "https://getcomposer.org/download/2.8.11/composer.phar",
-		MinPHPVersion:  "7.2.5",
-		MaxPHPVersion:  "8.4.99",
-		CompatiblePHP:  []string{"8.0", "8.1", "8.2", "8.3", "8.4"},
+		Version:       "2.8.11",
+		Released:      time.Date(2024, 8, 21, 0, 0, 0, 0, time.UTC),
+		URL:           "https://getcomposer.org/download/2.8.11/composer.phar",
+		Channel:       ComposerChannelStable,
+		MinPHPVersion: "7.2.5",
+	},
+	{
+		Version:       "2.7.9",
+		Released:      time.Date(2024, 6, 4, 0, 0, 0, 0, time.UTC),
+		URL:           "https://getcomposer.org/download/2.7.9/composer.phar",
+		Channel:       ComposerChannelStable,
+		MinPHPVersion: "7.2.5",
+		MaxPHPVersion: "8.3.99",
 	},
 	{
-		Version:        "2.7.9",
-		Released:       time.Date(2024, 6, 4, 0, 0, 0, 0, time.UTC),
-		URL:            "https://getcomposer.org/download/2.7.9/composer.phar",
-		MinPHPVersion:  "7.2.5",
-		MaxPHPVersion:  "8.3.99",
-		CompatiblePHP:  []string{"8.0", "8.1", "8.2", "8.3"},
+		Version:       "2.2.25",
+		URL:           "https://getcomposer.org/download/2.2.25/composer.phar",
+		Channel:       ComposerChannelLTS,
+		MinPHPVersion: "5.3.2",
 	},
 }
 
-// GetCompatibleComposerVersion returns the best Composer version for a given PHP version
-func GetCompatibleComposerVersion(phpVersion string) *ComposerVersion {
-	// Extract major.minor from PHP version (e.g., "8.4.1" -> "8.4")
-	phpMajorMinor := extractMajorMinor(phpVersion)
-	
-	// Find the latest compatible Composer version
+// SupportsPHP reports whether the Composer release runs on a PHP version
+func (c *ComposerVersion) SupportsPHP(phpVersion string) bool {
+	if c.MinPHPVersion != "" && CompareVersions(phpVersion, c.MinPHPVersion) < 0 {
+		return false
+	}
+	if c.MaxPHPVersion != "" && CompareVersions(phpVersion, c.MaxPHPVersion) > 0 {
+		return false
+	}
+	return true
+}
+
+// GetCompatibleComposerVersion returns the best Composer version for a given
+// PHP version from a catalog: the newest non-preview release whose supported
+// PHP range includes it
+func GetCompatibleComposerVersion(catalog []ComposerVersion, phpVersion string) *ComposerVersion {
 	var bestComposer *ComposerVersion
-	for i := range AvailableComposerVersions {
-		composer := &AvailableComposerVersions[i]
-		
-		// Check if PHP version is in compatible list
-		for _, compatiblePHP := range composer.CompatiblePHP {
-			if compatiblePHP == phpMajorMinor {
-				if bestComposer == nil || composer.Released.After(bestComposer.Released) {
-					bestComposer = composer
-				}
-				break
-			}
+	for i := range catalog {
+		composer := &catalog[i]
+		if composer.Channel == ComposerChannelPreview || !composer.SupportsPHP(phpVersion) {
+			continue
+		}
+		if bestComposer == nil || CompareVersions(composer.Version, bestComposer.Version) > 0 {
+			bestComposer = composer
 		}
 	}
-	
+
 	return bestComposer
 }
 
-// extractMajorMinor extracts major.minor version from a full version string
-func extractMajorMinor(version string) string {
-	parts := strings.Split(version, ".")
-	if len(parts) >= 2 {
-		return parts[0] + "." + parts[1]
+// FindComposerVersion returns the catalog entry for a Composer version. Versions
+// missing from the catalog (older 1.x releases, for example) are still
+// downloadable from getcomposer.org, so an entry is synthesized for them.
+func FindComposerVersion(catalog []ComposerVersion, version string) *ComposerVersion {
+	for i := range catalog {
+		if catalog[i].Version == version {
+			return &catalog[i]
+		}
+	}
+	return &ComposerVersion{
+		Version: version,
+		URL:     "https://getcomposer.org/download/" + version + "/composer.phar",
 	}
-	return version
 }